@@ -2,11 +2,10 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
-	"sync"
 
 	"github.com/armosec/armoapi-go/armotypes"
 	cs "github.com/armosec/armoapi-go/containerscan"
@@ -17,11 +16,12 @@ import (
 	wlidpkg "github.com/armosec/utils-k8s-go/wlid"
 	"github.com/hashicorp/go-multierror"
 	backendClientV1 "github.com/kubescape/backend/pkg/client/v1"
-	sysreport "github.com/kubescape/backend/pkg/server/v1/systemreports"
-	"github.com/kubescape/go-logger"
-	"github.com/kubescape/go-logger/helpers"
+	"github.com/kubescape/kubevuln/adapters/v1/events"
+	"github.com/kubescape/kubevuln/adapters/v1/postprocessors"
+	"github.com/kubescape/kubevuln/adapters/v1/tasks"
 	"github.com/kubescape/kubevuln/core/domain"
 	"github.com/kubescape/kubevuln/core/ports"
+	"github.com/kubescape/kubevuln/core/services"
 	"go.opentelemetry.io/otel"
 )
 
@@ -30,24 +30,168 @@ type BackendAdapter struct {
 	apiServerRestURL     string
 	clusterConfig        pkgcautils.ClusterConfig
 	getCVEExceptionsFunc func(string, string, *identifiers.PortalDesignator) ([]armotypes.VulnerabilityExceptionPolicy, error)
-	httpPostFunc         func(httputils.IHttpClient, string, map[string]string, []byte) (*http.Response, error)
-	sendStatusFunc       func(*backendClientV1.BaseReportSender, string, bool, chan<- error)
+	eventSink            ports.EventSink
+	postprocessors       *services.PostprocessorChain
+	taskRepository       ports.TaskRepository
 }
 
 var _ ports.Platform = (*BackendAdapter)(nil)
 
+// NewBackendAdapter constructs a BackendAdapter that talks to
+// eventReceiverRestURL using the same raw JSON wire format kubevuln has
+// always sent (events.LegacyHTTPEventSink), so it works against today's
+// event-receiver out of the box. Pass the sink built by events.NewHTTPEventSink
+// to WithEventSink once the receiver has been upgraded to accept CloudEvents.
 func NewBackendAdapter(accountID, apiServerRestURL, eventReceiverRestURL string) *BackendAdapter {
-	return &BackendAdapter{
+	a := &BackendAdapter{
 		clusterConfig: pkgcautils.ClusterConfig{
 			AccountID: accountID,
 		},
 		eventReceiverRestURL: eventReceiverRestURL,
 		apiServerRestURL:     apiServerRestURL,
 		getCVEExceptionsFunc: backendClientV1.GetCVEExceptionByDesignator,
-		httpPostFunc:         httputils.HttpPost,
-		sendStatusFunc: func(sender *backendClientV1.BaseReportSender, status string, sendReport bool, errChan chan<- error) {
-			sender.SendStatus(status, sendReport, errChan) // TODO - update this function to use from kubescape/backend
-		},
+		eventSink:            events.NewLegacyHTTPEventSink(eventReceiverRestURL),
+		taskRepository:       tasks.NewMemoryRepository(),
+	}
+
+	// seed from services.DefaultChain() so stages registered via
+	// services.RegisterPostprocessor at startup are picked up, then layer
+	// the built-in exception/relevancy/summarize pipeline on top
+	a.postprocessors = services.DefaultChain().Clone()
+	a.postprocessors.Register("exception", services.NewExceptionPostprocessor())
+	a.postprocessors.Register("relevancy", services.NewRelevancyPostprocessor())
+	a.postprocessors.Register("summarize", services.ReportPostprocessorFunc(a.summarizeStage))
+
+	return a
+}
+
+// summarizeStage wraps the package-private summarize helper as the chain's
+// terminal built-in stage, so it stays registered like any other
+// ReportPostprocessor instead of being a hardcoded call at the end of
+// SubmitCVE.
+func (a *BackendAdapter) summarizeStage(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	workload, ok := ctx.Value(domain.WorkloadKey{}).(domain.ScanCommand)
+	if !ok {
+		return nil, nil, domain.ErrCastingWorkload
+	}
+	_, hasRelevancy := services.RelevancyIndexFromContext(ctx)
+	summary, vulnerabilities := summarize(*report, vulnerabilities, workload, hasRelevancy)
+	report.Summary = summary
+	return report, vulnerabilities, nil
+}
+
+// ConfigurePostprocessors lets callers order/disable postprocessor stages
+// per-customer, e.g. from clusterConfig, so tenants can opt into heavier
+// enrichment without changing code.
+func (a *BackendAdapter) ConfigurePostprocessors(order []string, disabled []string) {
+	if len(order) > 0 {
+		a.postprocessors.Reorder(order...)
+	}
+	for _, name := range disabled {
+		a.postprocessors.SetEnabled(name, false)
+	}
+}
+
+// WithTaskRepository swaps the backing store for scan task state, e.g. to a
+// tasks.SQLRepository so in-flight scans survive a pod restart. The default
+// is an in-process tasks.MemoryRepository.
+func (a *BackendAdapter) WithTaskRepository(repository ports.TaskRepository) *BackendAdapter {
+	a.taskRepository = repository
+	return a
+}
+
+// ResumeInFlightScans hands every task not in a terminal state back to
+// resume, so a scan interrupted mid-flight (e.g. by a pod restart) picks up
+// from its last persisted domain.TaskState instead of being silently
+// abandoned. Call this once at startup, after WithTaskRepository if a
+// tasks.SQLRepository is in use - a fresh tasks.MemoryRepository never has
+// anything in flight to resume.
+func (a *BackendAdapter) ResumeInFlightScans(ctx context.Context, resume tasks.ResumableScan) error {
+	return tasks.NewManager(a.taskRepository).Resume(ctx, resume)
+}
+
+// WithEventSink swaps the transport used to ship scan status and result
+// events, e.g. to an MQTT/Kafka-backed events.BrokerEventSink instead of the
+// default HTTP event-receiver binding.
+func (a *BackendAdapter) WithEventSink(sink ports.EventSink) *BackendAdapter {
+	a.eventSink = sink
+	return a
+}
+
+// WithRelationalStore registers an optional "relational-fanout" chain stage
+// that converts each processed report via the same postprocessors.Converter
+// RelationalAdapter uses and upserts it into store, alongside (not instead
+// of) the eventSink send SubmitCVE already does - so a tenant can get both
+// event-receiver ingestion and deduplicated relational storage of the same
+// scan without standing up a second adapter.
+func (a *BackendAdapter) WithRelationalStore(store postprocessors.RelationalStore) *BackendAdapter {
+	a.postprocessors.Register("relational-fanout", services.ReportPostprocessorFunc(relationalFanoutStage(postprocessors.NewDefaultConverter(), store)))
+	return a
+}
+
+// WithSBOMLookup registers an "sbom-crossref" chain stage that attaches
+// package purl/license metadata from lookup to each vulnerability. The
+// default chain has no SBOM data source, since not every deployment
+// generates one.
+func (a *BackendAdapter) WithSBOMLookup(lookup services.SBOMLookup) *BackendAdapter {
+	a.postprocessors.Register("sbom-crossref", services.NewSBOMCrossReferencePostprocessor(lookup))
+	return a
+}
+
+// WithSinkSelector registers a terminal "sink-selector" chain stage that
+// forwards the fully-postprocessed report to whichever destination mode
+// selects, alongside (not instead of) the eventSink send SubmitCVE already
+// does - e.g. routing to a RelationalStore or a CloudEvents broker without
+// standing up a second adapter.
+func (a *BackendAdapter) WithSinkSelector(mode services.SinkMode, toReceiver, toStore, toBroker services.ReportSender) *BackendAdapter {
+	a.postprocessors.Register("sink-selector", services.NewSinkSelectorPostprocessor(mode, toReceiver, toStore, toBroker))
+	return a
+}
+
+// relationalFanoutStage builds the "relational-fanout" stage: it leaves
+// report/vulnerabilities untouched, so stages that run after it (e.g. a
+// sink-selector registered later) still see the same data SubmitCVE would
+// have produced without a RelationalStore configured. It is appended after
+// the built-in summarize stage, so it stores the summarized vulnerabilities.
+func relationalFanoutStage(converter postprocessors.Converter, store postprocessors.RelationalStore) func(context.Context, *v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	return func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+		workload, ok := ctx.Value(domain.WorkloadKey{}).(domain.ScanCommand)
+		if !ok {
+			return nil, nil, domain.ErrCastingWorkload
+		}
+
+		reportWithVulns := *report
+		reportWithVulns.Vulnerabilities = vulnerabilities
+		reportRow, records, joins, err := converter.ToRelationalSchema(ctx, &reportWithVulns, workload.Wlid)
+		if err != nil {
+			return nil, nil, fmt.Errorf("converting report to relational schema: %w", err)
+		}
+
+		if err := store.UpsertReport(ctx, reportRow); err != nil {
+			return nil, nil, fmt.Errorf("upserting scan report: %w", err)
+		}
+		if err := store.UpsertVulnerabilityRecords(ctx, records); err != nil {
+			return nil, nil, fmt.Errorf("upserting vulnerability records: %w", err)
+		}
+		if err := store.InsertJoinRows(ctx, joins); err != nil {
+			return nil, nil, fmt.Errorf("inserting join rows: %w", err)
+		}
+		return report, vulnerabilities, nil
+	}
+}
+
+// designatorsFromWorkload builds the CloudEvents designators shared by the
+// scan status and scan result events emitted for workload.
+func designatorsFromWorkload(accountID string, workload domain.ScanCommand) events.Designators {
+	return events.Designators{
+		CustomerGUID:  accountID,
+		Cluster:       wlidpkg.GetClusterFromWlid(workload.Wlid),
+		Namespace:     wlidpkg.GetNamespaceFromWlid(workload.Wlid),
+		Kind:          strings.ToLower(wlidpkg.GetKindFromWlid(workload.Wlid)),
+		Name:          wlidpkg.GetNameFromWlid(workload.Wlid),
+		ContainerName: workload.ContainerName,
+		JobID:         workload.JobID,
+		ParentJobID:   workload.ParentJobID,
 	}
 }
 
@@ -55,17 +199,15 @@ const ActionName = "vuln scan"
 const ReporterName = "ca-vuln-scan"
 const maxBodySize int = 30000
 
-var details = []string{
-	sysreport.JobStarted,
-	sysreport.JobStarted,
-	sysreport.JobSuccess,
-	sysreport.JobDone,
-}
+// statuses holds the transition detail for each legacyStepStates entry, in
+// the same order - step i's detail describes legacyStepStates[i], not the
+// legacy sysreport wording, so operators see a detail that actually matches
+// the state it's attached to.
 var statuses = []string{
 	"Inqueueing",
-	"Dequeueing",
-	"Dequeueing",
-	"Dequeueing",
+	"Scanning",
+	"Submitting",
+	"Done",
 }
 
 func (a *BackendAdapter) GetCVEExceptions(ctx context.Context) (domain.CVEExceptions, error) {
@@ -97,7 +239,21 @@ func (a *BackendAdapter) GetCVEExceptions(ctx context.Context) (domain.CVEExcept
 	return vulnExceptionList, nil
 }
 
-// SendStatus sends the given status and details to the platform
+// legacyStepStates maps the four SendStatus(ctx, step int) steps kubevuln
+// has always used onto the domain.ScanTask state machine, so existing
+// callers keep working unchanged while the underlying bookkeeping moves from
+// a LastAction+1 counter to a persisted, resumable task.
+var legacyStepStates = []domain.TaskState{
+	domain.TaskQueued,
+	domain.TaskScanning,
+	domain.TaskSubmitting,
+	domain.TaskDone,
+}
+
+// SendStatus transitions the scan's task to the state corresponding to step
+// and emits the resulting status event. It is kept for existing callers;
+// new code should prefer TransitionTask, which exposes the full
+// domain.TaskState machine instead of the four legacy step indices.
 func (a *BackendAdapter) SendStatus(ctx context.Context, step int) error {
 	ctx, span := otel.Tracer("").Start(ctx, "BackendAdapter.SendStatus")
 	defer span.End()
@@ -107,26 +263,54 @@ func (a *BackendAdapter) SendStatus(ctx context.Context, step int) error {
 		return domain.ErrCastingWorkload
 	}
 
-	lastAction := workload.LastAction + 1
-	report := sysreport.NewBaseReport(
-		a.clusterConfig.AccountID,
-		ReporterName,
-	)
-	report.Status = statuses[step]
-	report.Target = fmt.Sprintf("vuln scan:: scanning wlid: %v , container: %v imageTag: %v imageHash: %s",
+	return a.TransitionTask(ctx, workload.JobID, legacyStepStates[step], statuses[step])
+}
+
+// TransitionTask moves taskID to toState, persists the transition in the
+// task repository and emits a io.kubescape.kubevuln.scan.status.v1
+// CloudEvent carrying it, so a `GET /v1/tasks/{id}` caller and a CloudEvents
+// subscriber both see the same history.
+func (a *BackendAdapter) TransitionTask(ctx context.Context, taskID string, toState domain.TaskState, detail string) error {
+	ctx, span := otel.Tracer("").Start(ctx, "BackendAdapter.TransitionTask")
+	defer span.End()
+
+	workload, ok := ctx.Value(domain.WorkloadKey{}).(domain.ScanCommand)
+	if !ok {
+		return domain.ErrCastingWorkload
+	}
+	log := domain.LoggerFromContext(ctx)
+
+	// seed job_id/parent_job_id on first transition; a no-op if the task
+	// already exists (CreateTask is idempotent on a pre-existing ID)
+	if err := a.taskRepository.CreateTask(ctx, domain.ScanTask{ID: taskID, JobID: workload.JobID, ParentJobID: workload.ParentJobID}); err != nil {
+		log.Error("creating scan task", "taskID", taskID, "error", err)
+		return err
+	}
+
+	task, err := a.taskRepository.TransitionTask(ctx, taskID, toState, detail)
+	if err != nil {
+		log.Error("transitioning scan task", "taskID", taskID, "toState", string(toState), "error", err)
+		return err
+	}
+	var actionID string
+	if n := len(task.Transitions); n > 0 {
+		actionID = task.Transitions[n-1].ActionID
+	}
+	log.Debug("scan task transitioned", "taskID", taskID, "toState", string(toState), "actionID", actionID)
+
+	target := fmt.Sprintf("vuln scan:: scanning wlid: %v , container: %v imageTag: %v imageHash: %s",
 		workload.Wlid, workload.ContainerName, workload.ImageTagNormalized, workload.ImageHash)
-	report.ActionID = strconv.Itoa(lastAction)
-	report.ActionIDN = lastAction
-	report.ActionName = ActionName
-	report.JobID = workload.JobID
-	report.ParentAction = workload.ParentJobID
-	report.Details = details[step]
-
-	ReportErrorsChan := make(chan error)
-	sender := backendClientV1.NewBaseReportSender(a.eventReceiverRestURL, &http.Client{}, report)
-	a.sendStatusFunc(sender, sysreport.JobSuccess, true, ReportErrorsChan)
-	err := <-ReportErrorsChan
-	return err
+
+	event, err := events.NewStatusEvent(designatorsFromWorkload(a.clusterConfig.AccountID, workload), string(toState), detail, target, actionID)
+	if err != nil {
+		log.Error("building scan status event", "taskID", taskID, "error", err)
+		return err
+	}
+	if err := a.eventSink.Send(ctx, event); err != nil {
+		log.Error("sending scan status event", "taskID", taskID, "error", err)
+		return err
+	}
+	return nil
 }
 
 // SubmitCVE submits the given CVE to the platform
@@ -154,35 +338,33 @@ func (a *BackendAdapter) SubmitCVE(ctx context.Context, cve domain.CVEManifest,
 		return domain.ErrInvalidScanID
 	}
 
+	log := domain.LoggerFromContext(ctx)
+	log.Debug("submitting CVE report")
+
 	// get exceptions
 	exceptions, err := a.GetCVEExceptions(ctx)
 	if err != nil {
+		log.Error("getting CVE exceptions", "error", err)
 		return err
 	}
 	// convert to vulnerabilities
 	vulnerabilities, err := domainToArmo(ctx, *cve.Content, exceptions)
 	if err != nil {
+		log.Error("converting vulnerabilities", "error", err)
 		return err
 	}
-	// merge cve and cvep
-	var hasRelevancy bool
+	// stash the relevancy index on ctx for the "relevancy" postprocessor stage
 	if cvep.Content != nil {
-		hasRelevancy = true
-		// convert to relevantVulnerabilities
 		relevantVulnerabilities, err := domainToArmo(ctx, *cvep.Content, exceptions)
 		if err != nil {
+			log.Error("converting relevant vulnerabilities", "error", err)
 			return err
 		}
-		// index relevantVulnerabilities
 		cvepIndices := map[string]struct{}{}
 		for _, v := range relevantVulnerabilities {
 			cvepIndices[v.Name] = struct{}{}
 		}
-		// mark common vulnerabilities as relevant
-		for i, v := range vulnerabilities {
-			_, isRelevant := cvepIndices[v.Name]
-			vulnerabilities[i].IsRelevant = &isRelevant
-		}
+		ctx = services.ContextWithRelevancyIndex(ctx, cvepIndices)
 	}
 
 	finalReport := v1.ScanResultReport{
@@ -196,6 +378,10 @@ func (a *BackendAdapter) SubmitCVE(ctx context.Context, cve domain.CVEManifest,
 	finalReport.Designators.Attributes[identifiers.AttributeContainerName] = workload.ContainerName
 	finalReport.Designators.Attributes[identifiers.AttributeWorkloadHash] = cs.GenerateWorkloadHash(finalReport.Designators.Attributes)
 	finalReport.Designators.Attributes[identifiers.AttributeCustomerGUID] = a.clusterConfig.AccountID
+	finalReport.Designators.Attributes[identifiers.AttributeImageHash] = workload.ImageHash
+	if val, ok := workload.Args[identifiers.AttributeRegistrationUUID]; ok {
+		finalReport.Designators.Attributes[identifiers.AttributeRegistrationUUID] = val.(string)
+	}
 	if val, ok := workload.Args[identifiers.AttributeRegistryName]; ok {
 		finalReport.Designators.Attributes[identifiers.AttributeRegistryName] = val.(string)
 	}
@@ -216,44 +402,73 @@ func (a *BackendAdapter) SubmitCVE(ctx context.Context, cve domain.CVEManifest,
 		vulnerabilities[i].Designators = finalReport.Designators
 	}
 
-	// add summary
-	finalReport.Summary, vulnerabilities = summarize(finalReport, vulnerabilities, workload, hasRelevancy)
-	finalReport.Summary.Context = armoContext
-
-	// split vulnerabilities to chunks
-	chunksChan, totalVulnerabilities := httputils.SplitSlice2Chunks(vulnerabilities, maxBodySize, 10)
-
-	// send report(s)
-	sendWG := &sync.WaitGroup{}
-	errChan := make(chan error, 10)
-	// get the first chunk
-	firstVulnerabilitiesChunk := <-chunksChan
-	firstChunkVulnerabilitiesCount := len(firstVulnerabilitiesChunk)
-	// send the summary and the first chunk in one or two reports according to the size
-	nextPartNum := a.sendSummaryAndVulnerabilities(ctx, &finalReport, a.eventReceiverRestURL, totalVulnerabilities, scanID, firstVulnerabilitiesChunk, errChan, sendWG)
-	// if not all vulnerabilities got into the first chunk
-	if totalVulnerabilities != firstChunkVulnerabilitiesCount {
-		//send the rest of the vulnerabilities - error channel will be closed when all vulnerabilities are sent
-		a.sendVulnerabilitiesRoutine(ctx, chunksChan, a.eventReceiverRestURL, scanID, finalReport, errChan, sendWG, totalVulnerabilities, firstChunkVulnerabilitiesCount, nextPartNum)
-	} else {
-		//only one chunk will be sent so need to close the error channel when it is done
-		go func(wg *sync.WaitGroup, errorChan chan error) {
-			//wait for summary post request to end
-			wg.Wait()
-			//close the error channel
-			close(errorChan)
-		}(sendWG, errChan)
-	}
-
-	// collect post report errors if occurred
-	for e := range errChan {
-		err = multierror.Append(err, e)
+	// run the postprocessor chain (exception application, relevancy marking,
+	// summarization, plus anything registered via services.RegisterPostprocessor)
+	processedReport, vulnerabilities, err := a.postprocessors.Process(ctx, &finalReport, vulnerabilities)
+	if err != nil {
+		log.Error("running postprocessor chain", "error", err)
+		return err
+	}
+	finalReport = *processedReport
+	// Summary is only populated if the "summarize" stage ran; a tenant
+	// config that disables it (ConfigurePostprocessors(nil, []string{"summarize"}))
+	// is valid and must not panic here.
+	if finalReport.Summary != nil {
+		finalReport.Summary.Context = armoContext
+	}
+
+	// split vulnerabilities to chunks, each becoming one scan.result.v1 CloudEvent
+	chunksChan, _ := httputils.SplitSlice2Chunks(vulnerabilities, maxBodySize, 10)
+	var chunks [][]cs.CommonContainerVulnerabilityResult
+	for chunk := range chunksChan {
+		chunks = append(chunks, chunk)
+	}
+	if len(chunks) == 0 {
+		// a clean image has no vulnerabilities to chunk, but the scan still
+		// completed and its summary must still be recorded - send a single
+		// part carrying an empty vulnerability list.
+		chunks = append(chunks, nil)
+	}
+
+	designators := designatorsFromWorkload(a.clusterConfig.AccountID, workload)
+	designators.ScanID = scanID
+	designators.TotalParts = len(chunks)
+
+	for i, chunk := range chunks {
+		designators.PartNum = i + 1
+
+		chunkReport := finalReport
+		chunkReport.Vulnerabilities = chunk
+		if i > 0 {
+			// the summary only belongs on the first chunk
+			chunkReport.Summary = nil
+		}
+
+		payload, marshalErr := json.Marshal(chunkReport)
+		if marshalErr != nil {
+			err = multierror.Append(err, marshalErr)
+			continue
+		}
+
+		event, buildErr := events.NewResultEvent(designators, payload)
+		if buildErr != nil {
+			err = multierror.Append(err, buildErr)
+			continue
+		}
+
+		if sendErr := a.eventSink.Send(ctx, event); sendErr != nil {
+			err = multierror.Append(err, sendErr)
+		}
+	}
+
+	if err != nil {
+		log.Error("submitting CVE report", "error", err)
 	}
 	return err
 }
 
 //lint:ignore U1000 Ignore unused function temporarily for debugging
-func httpPostDebug(httpClient httputils.IHttpClient, fullURL string, headers map[string]string, body []byte) (*http.Response, error) {
-	logger.L().Debug("httpPostDebug", helpers.String("fullURL", fullURL), helpers.Interface("headers", headers), helpers.String("body", string(body)))
-	return httputils.HttpPostWithContext(context.Background(), httpClient, fullURL, headers, body)
+func httpPostDebug(ctx context.Context, httpClient httputils.IHttpClient, fullURL string, headers map[string]string, body []byte) (*http.Response, error) {
+	domain.LoggerFromContext(ctx).Trace("httpPostDebug", "fullURL", fullURL, "headers", headers, "body", string(body))
+	return httputils.HttpPostWithContext(ctx, httpClient, fullURL, headers, body)
 }
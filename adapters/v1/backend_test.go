@@ -0,0 +1,155 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+	"github.com/armosec/armoapi-go/identifiers"
+	"github.com/kubescape/kubevuln/adapters/v1/postprocessors"
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/kubescape/kubevuln/core/services"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRelationalStore records whatever the relational-fanout stage upserts,
+// so a test can assert on it without standing up a postprocessors.SQLStore.
+type fakeRelationalStore struct {
+	reports []postprocessors.ReportRow
+	records []postprocessors.VulnerabilityRecord
+	joins   []postprocessors.JoinRow
+}
+
+func (s *fakeRelationalStore) UpsertReport(ctx context.Context, report postprocessors.ReportRow) error {
+	s.reports = append(s.reports, report)
+	return nil
+}
+
+func (s *fakeRelationalStore) UpsertVulnerabilityRecords(ctx context.Context, records []postprocessors.VulnerabilityRecord) error {
+	s.records = append(s.records, records...)
+	return nil
+}
+
+func (s *fakeRelationalStore) InsertJoinRows(ctx context.Context, joins []postprocessors.JoinRow) error {
+	s.joins = append(s.joins, joins...)
+	return nil
+}
+
+var _ postprocessors.RelationalStore = (*fakeRelationalStore)(nil)
+
+// TestBackendAdapter_WithRelationalStore_PopulatesDedupKey drives the
+// "relational-fanout" stage WithRelationalStore registers with a report
+// shaped the way SubmitCVE now builds it - Designators.Attributes carrying
+// AttributeImageHash/AttributeRegistrationUUID - and asserts the store sees
+// a real dedup key, not the empty Digest/RegistrationUUID a report missing
+// those attributes would produce.
+func TestBackendAdapter_WithRelationalStore_PopulatesDedupKey(t *testing.T) {
+	a := NewBackendAdapter("account-1", "https://api.example.com", "https://receiver.example.com")
+	store := &fakeRelationalStore{}
+	a.WithRelationalStore(store)
+
+	ctx := context.WithValue(context.Background(), domain.WorkloadKey{}, domain.ScanCommand{
+		Wlid:          "wlid://cluster-x/namespace-y/deployment/my-app",
+		ContainerName: "nginx",
+	})
+
+	report := &v1.ScanResultReport{
+		ContainerScanID: "scan-1",
+		Timestamp:       1700000000,
+		Designators: identifiers.PortalDesignator{
+			Attributes: map[string]string{
+				identifiers.AttributeContainerName:    "nginx",
+				identifiers.AttributeImageHash:        "sha256:abc",
+				identifiers.AttributeRegistrationUUID: "reg-uuid-1",
+			},
+		},
+	}
+	vulnerabilities := []cs.CommonContainerVulnerabilityResult{
+		{Name: "CVE-2024-1", PackageName: "libssl", PackageVersion: "1.0", PackageType: "deb", Severity: "High"},
+	}
+
+	_, _, err := a.postprocessors.Process(ctx, report, vulnerabilities)
+	require.NoError(t, err)
+
+	require.Len(t, store.reports, 1)
+	require.Equal(t, "sha256:abc", store.reports[0].Digest)
+	require.Equal(t, "reg-uuid-1", store.reports[0].RegistrationUUID)
+
+	require.Len(t, store.records, 1)
+	require.Equal(t, "reg-uuid-1", store.records[0].RegistrationUUID)
+
+	require.Len(t, store.joins, 1)
+	require.Equal(t, "sha256:abc", store.joins[0].Digest)
+	require.Equal(t, "reg-uuid-1", store.joins[0].RegistrationUUID)
+}
+
+// TestBackendAdapter_WithSBOMLookup_AttachesPurlAndLicenses drives the
+// "sbom-crossref" stage WithSBOMLookup registers, asserting a matching SBOM
+// entry is attached to the vulnerability it was resolved for.
+func TestBackendAdapter_WithSBOMLookup_AttachesPurlAndLicenses(t *testing.T) {
+	a := NewBackendAdapter("account-1", "https://api.example.com", "https://receiver.example.com")
+	a.WithSBOMLookup(func(ctx context.Context, packageName, packageVersion string) (services.SBOMPackageInfo, bool) {
+		if packageName == "libssl" && packageVersion == "1.0" {
+			return services.SBOMPackageInfo{Purl: "pkg:deb/libssl@1.0", Licenses: []string{"OpenSSL"}}, true
+		}
+		return services.SBOMPackageInfo{}, false
+	})
+
+	// the built-in "summarize" stage reads the workload off ctx, as SubmitCVE provides it
+	ctx := context.WithValue(context.Background(), domain.WorkloadKey{}, domain.ScanCommand{
+		Wlid:          "wlid://cluster-x/namespace-y/deployment/my-app",
+		ContainerName: "nginx",
+	})
+
+	report := &v1.ScanResultReport{Designators: identifiers.PortalDesignator{Attributes: map[string]string{}}}
+	vulnerabilities := []cs.CommonContainerVulnerabilityResult{
+		{Name: "CVE-2024-1", PackageName: "libssl", PackageVersion: "1.0"},
+		{Name: "CVE-2024-2", PackageName: "curl", PackageVersion: "7.0"},
+	}
+
+	_, vulnerabilities, err := a.postprocessors.Process(ctx, report, vulnerabilities)
+	require.NoError(t, err)
+
+	require.Equal(t, "pkg:deb/libssl@1.0", vulnerabilities[0].PackagePURL)
+	require.Equal(t, []string{"OpenSSL"}, vulnerabilities[0].Licenses)
+	require.Empty(t, vulnerabilities[1].PackagePURL)
+}
+
+// TestBackendAdapter_WithSinkSelector_RoutesToConfiguredMode drives the
+// "sink-selector" stage WithSinkSelector registers, asserting it calls the
+// ReportSender matching the configured SinkMode and none of the others.
+func TestBackendAdapter_WithSinkSelector_RoutesToConfiguredMode(t *testing.T) {
+	a := NewBackendAdapter("account-1", "https://api.example.com", "https://receiver.example.com")
+
+	var sentToReceiver, sentToStore, sentToBroker bool
+	a.WithSinkSelector(
+		services.SinkModeRelationalStore,
+		func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) error {
+			sentToReceiver = true
+			return nil
+		},
+		func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) error {
+			sentToStore = true
+			return nil
+		},
+		func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) error {
+			sentToBroker = true
+			return nil
+		},
+	)
+
+	// the built-in "summarize" stage reads the workload off ctx, as SubmitCVE provides it
+	ctx := context.WithValue(context.Background(), domain.WorkloadKey{}, domain.ScanCommand{
+		Wlid:          "wlid://cluster-x/namespace-y/deployment/my-app",
+		ContainerName: "nginx",
+	})
+
+	report := &v1.ScanResultReport{Designators: identifiers.PortalDesignator{Attributes: map[string]string{}}}
+	_, _, err := a.postprocessors.Process(ctx, report, nil)
+	require.NoError(t, err)
+
+	require.False(t, sentToReceiver)
+	require.True(t, sentToStore)
+	require.False(t, sentToBroker)
+}
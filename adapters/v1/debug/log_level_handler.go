@@ -0,0 +1,46 @@
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/kubescape/kubevuln/core/domain"
+)
+
+// LogLevelHandler serves POST /debug/log-level, letting an operator raise a
+// running pod to Trace to capture a problematic scan's full body dumps and
+// drop back to Info without a restart. It is a plain http.Handler and must
+// be mounted by the caller, e.g. debugMux.Handle("/debug/log-level",
+// debug.NewLogLevelHandler()), alongside the rest of the app's debug routes.
+type LogLevelHandler struct{}
+
+func NewLogLevelHandler() *LogLevelHandler {
+	return &LogLevelHandler{}
+}
+
+type logLevelRequest struct {
+	Level string `json:"level"`
+}
+
+func (h *LogLevelHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req logLevelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	level := hclog.LevelFromString(req.Level)
+	if level == hclog.NoLevel {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	domain.SetLogLevel(level)
+	w.WriteHeader(http.StatusOK)
+}
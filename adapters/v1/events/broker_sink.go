@@ -0,0 +1,53 @@
+package events
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	kafka_sarama "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// BrokerEventSink publishes events to a Kafka topic instead of requiring the
+// event receiver to be directly reachable from every kubevuln instance.
+// This gives subscribers (policy engines, SIEMs, the relational store) at
+// least-once delivery semantics with broker-side retention rather than the
+// current fire-and-forget POSTs.
+type BrokerEventSink struct {
+	client cloudevents.Client
+}
+
+// NewKafkaEventSink builds a BrokerEventSink that publishes to topic on the
+// given Kafka brokers via the cloudevents-sdk-go Sarama protocol binding. A
+// similar binding exists for MQTT brokers; swap the protocol.Sender passed
+// to cloudevents.NewClient to use one.
+//
+// The IBM/sarama import above is deliberate, not a typo: kafka_sarama/v2
+// moved off Shopify/sarama onto the IBM/sarama fork after the upstream
+// rename, so it must be pinned to the same fork the binding itself imports.
+func NewKafkaEventSink(brokers []string, topic string, config *sarama.Config) (*BrokerEventSink, error) {
+	if config == nil {
+		config = sarama.NewConfig()
+		config.Version = sarama.V2_0_0_0
+	}
+
+	sender, err := kafka_sarama.NewSender(brokers, config, topic)
+	if err != nil {
+		return nil, fmt.Errorf("creating kafka sender for topic %s: %w", topic, err)
+	}
+
+	client, err := cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
+	if err != nil {
+		return nil, fmt.Errorf("creating CloudEvents kafka client: %w", err)
+	}
+
+	return &BrokerEventSink{client: client}, nil
+}
+
+func (s *BrokerEventSink) Send(ctx context.Context, event cloudevents.Event) error {
+	if result := s.client.Send(ctx, event); cloudevents.IsUndelivered(result) {
+		return fmt.Errorf("publishing event %s: %w", event.ID(), result)
+	}
+	return nil
+}
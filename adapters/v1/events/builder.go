@@ -0,0 +1,59 @@
+package events
+
+import (
+	"encoding/json"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/google/uuid"
+)
+
+func newEvent(eventType string, d Designators) cloudevents.Event {
+	event := cloudevents.NewEvent()
+	event.SetID(uuid.NewString())
+	event.SetSource(source)
+	event.SetType(eventType)
+	event.SetTime(time.Now())
+
+	event.SetExtension("customerguid", d.CustomerGUID)
+	event.SetExtension("cluster", d.Cluster)
+	event.SetExtension("namespace", d.Namespace)
+	event.SetExtension("kind", d.Kind)
+	event.SetExtension("name", d.Name)
+	event.SetExtension("containername", d.ContainerName)
+	event.SetExtension("scanid", d.ScanID)
+	event.SetExtension("partnum", d.PartNum)
+	event.SetExtension("totalparts", d.TotalParts)
+	event.SetExtension("jobid", d.JobID)
+	event.SetExtension("parentjobid", d.ParentJobID)
+
+	return event
+}
+
+// NewStatusEvent builds the CloudEvent emitted for one of a ScanTask's
+// transitions. actionID is the TaskTransition's ActionID (see
+// domain.TaskTransition), so a subscriber can correlate this event with the
+// same transition returned by `GET /v1/tasks/{id}`.
+func NewStatusEvent(d Designators, status, details, target, actionID string) (cloudevents.Event, error) {
+	event := newEvent(ScanStatusEventType, d)
+	event.SetExtension("actionid", actionID)
+	if err := event.SetData(cloudevents.ApplicationJSON, StatusData{
+		Status:  status,
+		Details: details,
+		Target:  target,
+	}); err != nil {
+		return cloudevents.Event{}, err
+	}
+	return event, nil
+}
+
+// NewResultEvent builds the CloudEvent carrying one chunk of a scan's
+// vulnerability results, matching the part numbering used by the previous
+// HTTP chunking scheme.
+func NewResultEvent(d Designators, payload json.RawMessage) (cloudevents.Event, error) {
+	event := newEvent(ScanResultEventType, d)
+	if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return cloudevents.Event{}, err
+	}
+	return event, nil
+}
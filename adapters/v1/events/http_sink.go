@@ -0,0 +1,82 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/armosec/utils-go/httputils"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// LegacyHTTPEventSink is the default HTTP transport, built to keep
+// SubmitCVE/SendStatus working against a receiver that has not been
+// upgraded to accept CloudEvents: a result event POSTs just its data
+// payload - the same raw ScanResultReport JSON the event-receiver has
+// always parsed - and a status event is rebuilt into the
+// sysreport.BaseReport shape SendStatus used to POST before CloudEvents
+// (see legacyBody), rather than either being wrapped in a CloudEvents
+// envelope. Use HTTPEventSink instead once the receiver on the other end
+// understands the CloudEvents HTTP binding.
+type LegacyHTTPEventSink struct {
+	client httputils.IHttpClient
+	target string
+}
+
+// NewLegacyHTTPEventSink builds a LegacyHTTPEventSink that POSTs to
+// targetURL, preserving the wire format kubevuln has always sent.
+func NewLegacyHTTPEventSink(targetURL string) *LegacyHTTPEventSink {
+	return &LegacyHTTPEventSink{client: &http.Client{}, target: targetURL}
+}
+
+func (s *LegacyHTTPEventSink) Send(ctx context.Context, event cloudevents.Event) error {
+	body, err := legacyBody(event)
+	if err != nil {
+		return fmt.Errorf("building legacy body for event %s: %w", event.ID(), err)
+	}
+
+	resp, err := httputils.HttpPostWithContext(ctx, s.client, s.target, map[string]string{"Content-Type": "application/json"}, body)
+	if err != nil {
+		return fmt.Errorf("posting event %s to %s: %w", event.ID(), s.target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("posting event %s to %s: unexpected status %s", event.ID(), s.target, resp.Status)
+	}
+	return nil
+}
+
+// HTTPEventSink delivers events to the event-receiver endpoint using the
+// CloudEvents HTTP binding, giving the event a well-typed CloudEvents
+// envelope instead of the raw chunked JSON POST LegacyHTTPEventSink sends.
+// targetURL must point at an endpoint that has been upgraded to accept
+// CloudEvents; switching a BackendAdapter to this sink (via WithEventSink)
+// is a breaking change from the raw sysreport/ScanResultReport JSON the
+// endpoint previously consumed, so it must be opted into once the receiver
+// on the other end is ready, not shipped as the default.
+type HTTPEventSink struct {
+	client cloudevents.Client
+	target string
+}
+
+// NewHTTPEventSink builds an HTTPEventSink that POSTs to targetURL using
+// the structured CloudEvents HTTP content mode, so the full envelope
+// (including the event's data) lands as a single self-contained JSON body
+// rather than splitting the event's metadata into ce-* headers a
+// not-yet-upgraded receiver would never look at.
+func NewHTTPEventSink(targetURL string) (*HTTPEventSink, error) {
+	client, err := cloudevents.NewClientHTTP(cehttp.WithStructuredEncoding())
+	if err != nil {
+		return nil, fmt.Errorf("creating CloudEvents HTTP client: %w", err)
+	}
+	return &HTTPEventSink{client: client, target: targetURL}, nil
+}
+
+func (s *HTTPEventSink) Send(ctx context.Context, event cloudevents.Event) error {
+	ctx = cloudevents.ContextWithTarget(ctx, s.target)
+	if result := s.client.Send(ctx, event); cloudevents.IsUndelivered(result) {
+		return fmt.Errorf("sending event %s to %s: %w", event.ID(), s.target, result)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPEventSink_Send_StructuredMode(t *testing.T) {
+	var body map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink, err := NewHTTPEventSink(server.URL)
+	require.NoError(t, err)
+
+	event, err := NewStatusEvent(Designators{ScanID: "scan-1"}, "Queued", "Inqueueing", "target", "1")
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), event))
+
+	// structured mode puts the whole CloudEvents envelope, including "data",
+	// in one JSON body - a receiver never upgraded to read ce-* headers can
+	// still recover the event type and payload from the body alone.
+	require.Equal(t, ScanStatusEventType, body["type"])
+	require.Contains(t, body, "data")
+}
+
+func TestLegacyHTTPEventSink_Send_ResultEvent_PostsRawPayload(t *testing.T) {
+	var bodyBytes []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		bodyBytes, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewLegacyHTTPEventSink(server.URL)
+
+	payload := json.RawMessage(`{"containerScanID":"scan-1"}`)
+	event, err := NewResultEvent(Designators{ScanID: "scan-1"}, payload)
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), event))
+
+	// a result event's body must be exactly the raw ScanResultReport JSON,
+	// unwrapped from the CloudEvent - the shape the event-receiver has
+	// always parsed.
+	require.JSONEq(t, string(payload), string(bodyBytes))
+}
+
+func TestLegacyHTTPEventSink_Send_StatusEvent_PreservesLegacyFields(t *testing.T) {
+	var bodyBytes []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+		bodyBytes, err = io.ReadAll(r.Body)
+		require.NoError(t, err)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewLegacyHTTPEventSink(server.URL)
+
+	designators := Designators{CustomerGUID: "cust-1", JobID: "job-1", ParentJobID: "parent-1"}
+	event, err := NewStatusEvent(designators, "Queued", "Inqueueing", "target", "42")
+	require.NoError(t, err)
+
+	require.NoError(t, sink.Send(context.Background(), event))
+
+	// StatusData alone (status/details/target) drops the fields the legacy
+	// sysreport.BaseReport carried - assert the posted body actually carries
+	// the job/action identifiers a receiver correlates a status update by,
+	// not just the bare CloudEvents "data" payload.
+	body := string(bodyBytes)
+	require.Contains(t, body, "job-1")
+	require.Contains(t, body, "parent-1")
+	require.Contains(t, body, "42")
+
+	var asMap map[string]interface{}
+	require.NoError(t, json.Unmarshal(bodyBytes, &asMap))
+	require.Greater(t, len(asMap), 3, "legacy status body should carry more than StatusData's status/details/target")
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	sysreport "github.com/kubescape/backend/pkg/server/v1/systemreports"
+)
+
+// legacyActionName and legacyReporterName are the values the pre-CloudEvents
+// SendStatus always stamped on its sysreport.BaseReport.
+const (
+	legacyActionName   = "vuln scan"
+	legacyReporterName = "ca-vuln-scan"
+)
+
+// legacyBody returns the bytes LegacyHTTPEventSink POSTs for event: the
+// result event's data is already the raw ScanResultReport JSON the
+// event-receiver has always parsed, so it is returned as-is; a status event
+// carries only StatusData, which on its own drops the
+// ActionID/ActionIDN/JobID/ParentAction/CustomerGUID fields the receiver
+// needs to attribute a status update, so it is rebuilt into the same
+// sysreport.BaseReport shape SendStatus used to POST directly.
+func legacyBody(event cloudevents.Event) ([]byte, error) {
+	if event.Type() != ScanStatusEventType {
+		return event.Data(), nil
+	}
+
+	var data StatusData
+	if err := event.DataAs(&data); err != nil {
+		return nil, fmt.Errorf("decoding status event data: %w", err)
+	}
+
+	report := sysreport.NewBaseReport(extensionString(event, "customerguid"), legacyReporterName)
+	report.Status = data.Status
+	report.Details = data.Details
+	report.Target = data.Target
+	report.ActionName = legacyActionName
+	report.JobID = extensionString(event, "jobid")
+	report.ParentAction = extensionString(event, "parentjobid")
+
+	actionID := extensionString(event, "actionid")
+	report.ActionID = actionID
+	if actionIDN, err := strconv.Atoi(actionID); err == nil {
+		report.ActionIDN = actionIDN
+	}
+
+	return json.Marshal(report)
+}
+
+// extensionString reads a CloudEvents extension attribute set by newEvent,
+// falling back to "" rather than erroring: a missing designator shouldn't
+// stop the status update from being sent.
+func extensionString(event cloudevents.Event, name string) string {
+	v, ok := event.Extensions()[name]
+	if !ok {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
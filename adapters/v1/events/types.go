@@ -0,0 +1,38 @@
+package events
+
+// CloudEvents types emitted for each scan. Consumers (policy engines, SIEMs,
+// the relational store, ...) can subscribe to these instead of requiring the
+// event receiver to be directly reachable from every kubevuln instance.
+const (
+	// ScanStatusEventType is emitted for each ScanTask state transition,
+	// including the four legacy SendStatus steps.
+	ScanStatusEventType = "io.kubescape.kubevuln.scan.status.v1"
+	// ScanResultEventType is emitted for each chunk of a scan's vulnerability results.
+	ScanResultEventType = "io.kubescape.kubevuln.scan.result.v1"
+
+	source = "kubescape/kubevuln"
+)
+
+// StatusData is the JSON payload carried by a ScanStatusEventType event.
+type StatusData struct {
+	Status  string `json:"status"`
+	Details string `json:"details"`
+	Target  string `json:"target"`
+}
+
+// Designators carries the scan attributes every kubevuln CloudEvent is
+// stamped with as CE extension attributes, mirroring the designators
+// BackendAdapter already threads through SubmitCVE and SendStatus.
+type Designators struct {
+	CustomerGUID  string
+	Cluster       string
+	Namespace     string
+	Kind          string
+	Name          string
+	ContainerName string
+	ScanID        string
+	PartNum       int
+	TotalParts    int
+	JobID         string
+	ParentJobID   string
+}
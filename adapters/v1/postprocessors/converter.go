@@ -0,0 +1,144 @@
+package postprocessors
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+	"github.com/armosec/armoapi-go/identifiers"
+	"github.com/kubescape/kubevuln/core/domain"
+)
+
+// ReportRow is the per-scan row of the relational schema. It carries the
+// designators needed to answer "what was scanned" without touching the
+// (deduplicated) vulnerability data itself.
+type ReportRow struct {
+	ScanID           string
+	Digest           string
+	RegistrationUUID string
+	MimeType         string
+	ContainerName    string
+	Wlid             string
+	Timestamp        int64
+}
+
+// VulnerabilityRecord is a single, deduplicated vulnerability as reported by
+// the scanner. Records are keyed by (CVEID, RegistrationUUID, Package,
+// PackageVersion, PackageType, Severity) so the same CVE affecting the same
+// package across many images is stored once.
+type VulnerabilityRecord struct {
+	CVEID            string
+	RegistrationUUID string
+	Package          string
+	PackageVersion   string
+	PackageType      string
+	Severity         string
+	Description      string
+}
+
+// JoinRow links a VulnerabilityRecord to the ReportRow it was observed in,
+// together with the fields that only make sense per-occurrence.
+type JoinRow struct {
+	ScanID           string
+	Digest           string
+	RegistrationUUID string
+	CVEID            string
+	Package          string
+	PackageVersion   string
+	PackageType      string
+	Severity         string
+	FixVersion       string
+	IsRelevant       bool
+	ExceptionApplied bool
+}
+
+// Converter turns a scanner-produced report into the relational schema
+// described above, shared by both RelationalAdapter.SubmitCVE and
+// BackendAdapter's optional "relational-fanout" postprocessor stage (see
+// BackendAdapter.WithRelationalStore), so the two adapters don't each carry
+// their own copy of the report->schema mapping. wlid is passed in explicitly
+// rather than read off the report: the report's Designators only carry the
+// WLID's decomposed cluster/namespace/kind/name attributes, not the WLID
+// itself.
+type Converter interface {
+	ToRelationalSchema(ctx context.Context, report *v1.ScanResultReport, wlid string) (ReportRow, []VulnerabilityRecord, []JoinRow, error)
+}
+
+// DefaultConverter implements Converter by walking the already-built
+// ScanResultReport's Vulnerabilities, which is the shape both adapters have
+// on hand by the time they need to persist a scan.
+type DefaultConverter struct{}
+
+var _ Converter = (*DefaultConverter)(nil)
+
+func NewDefaultConverter() *DefaultConverter {
+	return &DefaultConverter{}
+}
+
+func (c *DefaultConverter) ToRelationalSchema(ctx context.Context, report *v1.ScanResultReport, wlid string) (ReportRow, []VulnerabilityRecord, []JoinRow, error) {
+	if report == nil {
+		return ReportRow{}, nil, nil, domain.ErrMissingTimestamp
+	}
+
+	registrationUUID := report.Designators.Attributes[identifiers.AttributeRegistrationUUID]
+	reportRow := ReportRow{
+		ScanID:           report.ContainerScanID,
+		Digest:           report.Designators.Attributes[identifiers.AttributeImageHash],
+		RegistrationUUID: registrationUUID,
+		MimeType:         "application/vnd.kubescape.scan-result.v1+json",
+		ContainerName:    report.Designators.Attributes[identifiers.AttributeContainerName],
+		Wlid:             wlid,
+		Timestamp:        report.Timestamp,
+	}
+
+	records := make(map[string]VulnerabilityRecord)
+	joins := make([]JoinRow, 0, len(report.Vulnerabilities))
+
+	for _, vuln := range report.Vulnerabilities {
+		key := recordKey(vuln.Name, registrationUUID, vuln.PackageName, vuln.PackageVersion, vuln.PackageType, vuln.Severity)
+		if _, ok := records[key]; !ok {
+			records[key] = VulnerabilityRecord{
+				CVEID:            vuln.Name,
+				RegistrationUUID: registrationUUID,
+				Package:          vuln.PackageName,
+				PackageVersion:   vuln.PackageVersion,
+				PackageType:      vuln.PackageType,
+				Severity:         vuln.Severity,
+				Description:      vuln.Description,
+			}
+		}
+
+		isRelevant := vuln.IsRelevant != nil && *vuln.IsRelevant
+		joins = append(joins, JoinRow{
+			ScanID:           reportRow.ScanID,
+			Digest:           reportRow.Digest,
+			RegistrationUUID: registrationUUID,
+			CVEID:            vuln.Name,
+			Package:          vuln.PackageName,
+			PackageVersion:   vuln.PackageVersion,
+			PackageType:      vuln.PackageType,
+			Severity:         vuln.Severity,
+			FixVersion:       vuln.FixVersion,
+			IsRelevant:       isRelevant,
+			ExceptionApplied: vuln.ExceptionApplied,
+		})
+	}
+
+	out := make([]VulnerabilityRecord, 0, len(records))
+	for _, r := range records {
+		out = append(out, r)
+	}
+
+	return reportRow, out, joins, nil
+}
+
+func recordKey(cveID, registrationUUID, pkg, pkgVersion, pkgType, severity string) string {
+	return cveID + "|" + registrationUUID + "|" + pkg + "|" + pkgVersion + "|" + pkgType + "|" + severity
+}
+
+// timestampToTime is a small helper for storage backends that need a
+// time.Time for indexing/retention rather than the raw unix timestamp.
+// SQLStore stores the raw unix timestamp and does not call it.
+func timestampToTime(unix int64) time.Time {
+	return time.Unix(unix, 0).UTC()
+}
@@ -0,0 +1,65 @@
+package postprocessors
+
+import (
+	"context"
+	"testing"
+
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+	"github.com/armosec/armoapi-go/identifiers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestDefaultConverter_ToRelationalSchema(t *testing.T) {
+	report := &v1.ScanResultReport{
+		ContainerScanID: "scan-1",
+		Timestamp:       1700000000,
+		Designators: identifiers.PortalDesignator{
+			Attributes: map[string]string{
+				identifiers.AttributeImageHash:        "sha256:abc",
+				identifiers.AttributeRegistrationUUID: "reg-uuid-1",
+				identifiers.AttributeContainerName:    "nginx",
+			},
+		},
+		Vulnerabilities: []cs.CommonContainerVulnerabilityResult{
+			{
+				Name: "CVE-2024-1", PackageName: "libssl", PackageVersion: "1.0",
+				PackageType: "deb", Severity: "High", FixVersion: "1.1", IsRelevant: boolPtr(true),
+			},
+			{
+				// same dedup key as above - must collapse into a single record
+				Name: "CVE-2024-1", PackageName: "libssl", PackageVersion: "1.0",
+				PackageType: "deb", Severity: "High", FixVersion: "1.1", IsRelevant: boolPtr(false),
+			},
+			{
+				Name: "CVE-2024-2", PackageName: "curl", PackageVersion: "7.0",
+				PackageType: "deb", Severity: "Medium",
+			},
+		},
+	}
+
+	converter := NewDefaultConverter()
+	row, records, joins, err := converter.ToRelationalSchema(context.Background(), report, "wlid://cluster-x/namespace-y/deployment/my-app")
+	require.NoError(t, err)
+
+	assert.Equal(t, "scan-1", row.ScanID)
+	assert.Equal(t, "sha256:abc", row.Digest)
+	assert.Equal(t, "reg-uuid-1", row.RegistrationUUID)
+	assert.Equal(t, "nginx", row.ContainerName)
+	assert.Equal(t, "wlid://cluster-x/namespace-y/deployment/my-app", row.Wlid)
+	assert.NotEmpty(t, row.Digest)
+	assert.NotEmpty(t, row.RegistrationUUID)
+
+	// CVE-2024-1/libssl is reported twice but must dedup to a single record
+	assert.Len(t, records, 2)
+	assert.Len(t, joins, 3)
+}
+
+func TestDefaultConverter_ToRelationalSchema_NilReport(t *testing.T) {
+	converter := NewDefaultConverter()
+	_, _, _, err := converter.ToRelationalSchema(context.Background(), nil, "wlid://cluster-x/namespace-y/deployment/my-app")
+	assert.Error(t, err)
+}
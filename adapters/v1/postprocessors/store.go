@@ -0,0 +1,99 @@
+package postprocessors
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	// database/sql drivers for NewPostgresStore/NewSQLiteStore, registered
+	// under the "pgx" and "sqlite" names sql.Open expects below.
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+)
+
+// RelationalStore persists the relational schema produced by a Converter.
+// Implementations must upsert VulnerabilityRecords (so the same CVE/package
+// pair across many scans is stored once) and simply insert JoinRows, which
+// are per-occurrence.
+type RelationalStore interface {
+	UpsertReport(ctx context.Context, report ReportRow) error
+	UpsertVulnerabilityRecords(ctx context.Context, records []VulnerabilityRecord) error
+	InsertJoinRows(ctx context.Context, joins []JoinRow) error
+}
+
+// SQLStore is a database/sql-backed RelationalStore. It works against any
+// driver that supports upserts via "INSERT ... ON CONFLICT", which covers
+// both the Postgres and SQLite backends below.
+type SQLStore struct {
+	db *sql.DB
+}
+
+var _ RelationalStore = (*SQLStore)(nil)
+
+// NewPostgresStore opens a SQLStore against a Postgres DSN. Callers are
+// expected to have applied migrations/0001_relational_vulnerability_schema.sql.
+func NewPostgresStore(ctx context.Context, dsn string) (*SQLStore, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("opening postgres store: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("pinging postgres store: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// NewSQLiteStore opens a SQLStore backed by SQLite, primarily intended for
+// tests and single-node deployments where a Postgres instance isn't worth
+// running.
+func NewSQLiteStore(ctx context.Context, path string) (*SQLStore, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening sqlite store: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("pinging sqlite store: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+func (s *SQLStore) UpsertReport(ctx context.Context, report ReportRow) error {
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO scan_report (scan_id, digest, registration_uuid, mime_type, container_name, wlid, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (scan_id) DO UPDATE SET
+			digest = excluded.digest,
+			mime_type = excluded.mime_type,
+			timestamp = excluded.timestamp
+	`, report.ScanID, report.Digest, report.RegistrationUUID, report.MimeType, report.ContainerName, report.Wlid, report.Timestamp)
+	return err
+}
+
+func (s *SQLStore) UpsertVulnerabilityRecords(ctx context.Context, records []VulnerabilityRecord) error {
+	for _, r := range records {
+		if _, err := s.db.ExecContext(ctx, `
+			INSERT INTO vulnerability_record (cve_id, registration_uuid, package, package_version, package_type, severity, description)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (cve_id, registration_uuid, package, package_version, package_type, severity) DO NOTHING
+		`, r.CVEID, r.RegistrationUUID, r.Package, r.PackageVersion, r.PackageType, r.Severity, r.Description); err != nil {
+			return fmt.Errorf("upserting vulnerability_record %s/%s: %w", r.CVEID, r.Package, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) InsertJoinRows(ctx context.Context, joins []JoinRow) error {
+	for _, j := range joins {
+		if _, err := s.db.ExecContext(ctx, `
+			INSERT INTO scan_vulnerability (scan_id, digest, registration_uuid, cve_id, package, package_version, package_type, severity, fix_version, is_relevant, exception_applied)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, j.ScanID, j.Digest, j.RegistrationUUID, j.CVEID, j.Package, j.PackageVersion, j.PackageType, j.Severity, j.FixVersion, j.IsRelevant, j.ExceptionApplied); err != nil {
+			return fmt.Errorf("inserting scan_vulnerability join row for %s: %w", j.CVEID, err)
+		}
+	}
+	return nil
+}
+
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,73 @@
+package postprocessors
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLiteStore(t *testing.T) *SQLStore {
+	t.Helper()
+	ctx := context.Background()
+
+	store, err := NewSQLiteStore(ctx, ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	// :memory: sqlite databases are per-connection; pin the pool to one
+	// connection so the schema applied below is visible to every query.
+	store.db.SetMaxOpenConns(1)
+
+	schema, err := os.ReadFile("migrations/0001_relational_vulnerability_schema.sql")
+	require.NoError(t, err)
+	_, err = store.db.ExecContext(ctx, string(schema))
+	require.NoError(t, err)
+
+	return store
+}
+
+func TestSQLStore_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store := newTestSQLiteStore(t)
+
+	report := ReportRow{
+		ScanID:           "scan-1",
+		Digest:           "sha256:abc",
+		RegistrationUUID: "reg-uuid-1",
+		MimeType:         "application/vnd.kubescape.scan-result.v1+json",
+		ContainerName:    "nginx",
+		Wlid:             "wlid://cluster-x/namespace-y/deployment/my-app",
+		Timestamp:        1700000000,
+	}
+	require.NoError(t, store.UpsertReport(ctx, report))
+
+	records := []VulnerabilityRecord{
+		{
+			CVEID: "CVE-2024-1", RegistrationUUID: "reg-uuid-1", Package: "libssl",
+			PackageVersion: "1.0", PackageType: "deb", Severity: "High", Description: "bad",
+		},
+	}
+	require.NoError(t, store.UpsertVulnerabilityRecords(ctx, records))
+	// upserting the same record twice must stay idempotent (ON CONFLICT DO NOTHING)
+	require.NoError(t, store.UpsertVulnerabilityRecords(ctx, records))
+
+	joins := []JoinRow{
+		{
+			ScanID: "scan-1", Digest: "sha256:abc", RegistrationUUID: "reg-uuid-1",
+			CVEID: "CVE-2024-1", Package: "libssl", PackageVersion: "1.0", PackageType: "deb",
+			Severity: "High", FixVersion: "1.1", IsRelevant: true,
+		},
+	}
+	require.NoError(t, store.InsertJoinRows(ctx, joins))
+
+	var scanCount, recordCount, joinCount int
+	require.NoError(t, store.db.QueryRowContext(ctx, `SELECT count(*) FROM scan_report`).Scan(&scanCount))
+	require.NoError(t, store.db.QueryRowContext(ctx, `SELECT count(*) FROM vulnerability_record`).Scan(&recordCount))
+	require.NoError(t, store.db.QueryRowContext(ctx, `SELECT count(*) FROM scan_vulnerability`).Scan(&joinCount))
+
+	require.Equal(t, 1, scanCount)
+	require.Equal(t, 1, recordCount)
+	require.Equal(t, 1, joinCount)
+}
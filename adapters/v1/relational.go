@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"strings"
+
+	"github.com/armosec/armoapi-go/armotypes"
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+	"github.com/armosec/armoapi-go/identifiers"
+	pkgcautils "github.com/armosec/utils-k8s-go/armometadata"
+	wlidpkg "github.com/armosec/utils-k8s-go/wlid"
+	backendClientV1 "github.com/kubescape/backend/pkg/client/v1"
+	"github.com/kubescape/kubevuln/adapters/v1/postprocessors"
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/kubescape/kubevuln/core/ports"
+	"go.opentelemetry.io/otel"
+)
+
+// RelationalAdapter is a ports.Platform implementation that, unlike
+// BackendAdapter, does not POST the full vulnerability list as JSON chunks.
+// Instead it converts each scan into the normalized relational schema (see
+// postprocessors.Converter) and persists it through a RelationalStore, so
+// the same CVE affecting the same package is stored once regardless of how
+// many images it shows up in.
+type RelationalAdapter struct {
+	clusterConfig        pkgcautils.ClusterConfig
+	apiServerRestURL     string
+	getCVEExceptionsFunc func(string, string, *identifiers.PortalDesignator) ([]armotypes.VulnerabilityExceptionPolicy, error)
+	converter            postprocessors.Converter
+	store                postprocessors.RelationalStore
+}
+
+var _ ports.Platform = (*RelationalAdapter)(nil)
+
+// NewRelationalAdapter constructs a RelationalAdapter backed by the given
+// RelationalStore. The store is expected to already have the schema in
+// postprocessors/migrations applied.
+func NewRelationalAdapter(accountID, apiServerRestURL string, store postprocessors.RelationalStore) *RelationalAdapter {
+	return &RelationalAdapter{
+		clusterConfig: pkgcautils.ClusterConfig{
+			AccountID: accountID,
+		},
+		apiServerRestURL:     apiServerRestURL,
+		getCVEExceptionsFunc: backendClientV1.GetCVEExceptionByDesignator,
+		converter:            postprocessors.NewDefaultConverter(),
+		store:                store,
+	}
+}
+
+// GetCVEExceptions retrieves the exception policies for the workload on the
+// context, identically to BackendAdapter.GetCVEExceptions.
+func (a *RelationalAdapter) GetCVEExceptions(ctx context.Context) (domain.CVEExceptions, error) {
+	ctx, span := otel.Tracer("").Start(ctx, "RelationalAdapter.GetCVEExceptions")
+	defer span.End()
+
+	workload, ok := ctx.Value(domain.WorkloadKey{}).(domain.ScanCommand)
+	if !ok {
+		return nil, domain.ErrCastingWorkload
+	}
+
+	designator := identifiers.PortalDesignator{
+		DesignatorType: identifiers.DesignatorAttribute,
+		Attributes: map[string]string{
+			"customerGUID":        a.clusterConfig.AccountID,
+			"scope.cluster":       wlidpkg.GetClusterFromWlid(workload.Wlid),
+			"scope.namespace":     wlidpkg.GetNamespaceFromWlid(workload.Wlid),
+			"scope.kind":          strings.ToLower(wlidpkg.GetKindFromWlid(workload.Wlid)),
+			"scope.name":          wlidpkg.GetNameFromWlid(workload.Wlid),
+			"scope.containerName": workload.ContainerName,
+		},
+	}
+
+	vulnExceptionList, err := a.getCVEExceptionsFunc(a.apiServerRestURL, a.clusterConfig.AccountID, &designator)
+	if err != nil {
+		return nil, err
+	}
+	return vulnExceptionList, nil
+}
+
+// SendStatus is a no-op status reporter for the relational adapter today:
+// progress reporting still goes through the existing event-receiver backed
+// BackendAdapter. A task-based replacement is tracked separately.
+func (a *RelationalAdapter) SendStatus(ctx context.Context, step int) error {
+	return nil
+}
+
+// SubmitCVE builds a ScanResultReport/vulnerability list the way
+// BackendAdapter does - without the registry name, repository, tag and
+// sensor designators, the per-vulnerability context, or BackendAdapter's
+// postprocessor chain - but instead of chunking it into JSON POSTs, converts it into the
+// normalized relational schema via the configured Converter and upserts it
+// into the RelationalStore: vulnerability_records are deduplicated across
+// scans, while the join rows capture per-occurrence fields (fix version,
+// relevancy, exception status).
+func (a *RelationalAdapter) SubmitCVE(ctx context.Context, cve domain.CVEManifest, cvep domain.CVEManifest) error {
+	ctx, span := otel.Tracer("").Start(ctx, "RelationalAdapter.SubmitCVE")
+	defer span.End()
+
+	timestamp, ok := ctx.Value(domain.TimestampKey{}).(int64)
+	if !ok {
+		return domain.ErrMissingTimestamp
+	}
+	scanID, ok := ctx.Value(domain.ScanIDKey{}).(string)
+	if !ok {
+		return domain.ErrMissingScanID
+	}
+	workload, ok := ctx.Value(domain.WorkloadKey{}).(domain.ScanCommand)
+	if !ok {
+		return domain.ErrCastingWorkload
+	}
+	if !armotypes.ValidateContainerScanID(scanID) {
+		return domain.ErrInvalidScanID
+	}
+
+	log := domain.LoggerFromContext(ctx)
+	log.Debug("submitting CVE report to relational store")
+
+	exceptions, err := a.GetCVEExceptions(ctx)
+	if err != nil {
+		log.Error("getting CVE exceptions", "error", err)
+		return err
+	}
+	vulnerabilities, err := domainToArmo(ctx, *cve.Content, exceptions)
+	if err != nil {
+		log.Error("converting vulnerabilities", "error", err)
+		return err
+	}
+	if cvep.Content != nil {
+		relevantVulnerabilities, err := domainToArmo(ctx, *cvep.Content, exceptions)
+		if err != nil {
+			log.Error("converting relevant vulnerabilities", "error", err)
+			return err
+		}
+		cvepIndices := map[string]struct{}{}
+		for _, v := range relevantVulnerabilities {
+			cvepIndices[v.Name] = struct{}{}
+		}
+		for i, v := range vulnerabilities {
+			_, isRelevant := cvepIndices[v.Name]
+			vulnerabilities[i].IsRelevant = &isRelevant
+		}
+	}
+
+	finalReport := v1.ScanResultReport{
+		Designators:     *identifiers.AttributesDesignatorsFromWLID(workload.Wlid),
+		ContainerScanID: scanID,
+		Timestamp:       timestamp,
+	}
+	finalReport.Designators.Attributes[identifiers.AttributeContainerName] = workload.ContainerName
+	finalReport.Designators.Attributes[identifiers.AttributeWorkloadHash] = cs.GenerateWorkloadHash(finalReport.Designators.Attributes)
+	finalReport.Designators.Attributes[identifiers.AttributeCustomerGUID] = a.clusterConfig.AccountID
+	finalReport.Designators.Attributes[identifiers.AttributeImageHash] = workload.ImageHash
+	if val, ok := workload.Args[identifiers.AttributeRegistrationUUID]; ok {
+		finalReport.Designators.Attributes[identifiers.AttributeRegistrationUUID] = val.(string)
+	}
+
+	finalReport.Summary, vulnerabilities = summarize(finalReport, vulnerabilities, workload, cvep.Content != nil)
+	finalReport.Vulnerabilities = vulnerabilities
+
+	reportRow, records, joins, err := a.converter.ToRelationalSchema(ctx, &finalReport, workload.Wlid)
+	if err != nil {
+		log.Error("converting report to relational schema", "error", err)
+		return err
+	}
+
+	if err := a.store.UpsertReport(ctx, reportRow); err != nil {
+		log.Error("upserting scan report", "error", err)
+		return err
+	}
+	if err := a.store.UpsertVulnerabilityRecords(ctx, records); err != nil {
+		log.Error("upserting vulnerability records", "error", err)
+		return err
+	}
+	if err := a.store.InsertJoinRows(ctx, joins); err != nil {
+		log.Error("inserting join rows", "error", err)
+		return err
+	}
+	return nil
+}
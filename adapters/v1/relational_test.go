@@ -0,0 +1,39 @@
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/armosec/armoapi-go/armotypes"
+	"github.com/armosec/armoapi-go/identifiers"
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRelationalAdapter_GetCVEExceptions_DecomposesWLID checks that the
+// designator sent to getCVEExceptionsFunc carries the WLID's decomposed
+// cluster/namespace/kind/name, not the raw WLID string.
+func TestRelationalAdapter_GetCVEExceptions_DecomposesWLID(t *testing.T) {
+	a := NewRelationalAdapter("account-1", "https://api.example.com", &fakeRelationalStore{})
+
+	var gotDesignator *identifiers.PortalDesignator
+	a.getCVEExceptionsFunc = func(apiServerRestURL, accountID string, designator *identifiers.PortalDesignator) ([]armotypes.VulnerabilityExceptionPolicy, error) {
+		gotDesignator = designator
+		return nil, nil
+	}
+
+	ctx := context.WithValue(context.Background(), domain.WorkloadKey{}, domain.ScanCommand{
+		Wlid:          "wlid://cluster-x/namespace-y/deployment/my-app",
+		ContainerName: "nginx",
+	})
+
+	_, err := a.GetCVEExceptions(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, gotDesignator)
+
+	require.Equal(t, "cluster-x", gotDesignator.Attributes["scope.cluster"])
+	require.Equal(t, "namespace-y", gotDesignator.Attributes["scope.namespace"])
+	require.Equal(t, "deployment", gotDesignator.Attributes["scope.kind"])
+	require.Equal(t, "my-app", gotDesignator.Attributes["scope.name"])
+	require.Equal(t, "nginx", gotDesignator.Attributes["scope.containerName"])
+}
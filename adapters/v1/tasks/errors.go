@@ -0,0 +1,7 @@
+package tasks
+
+import "errors"
+
+// ErrTaskNotFound is returned by a TaskRepository when no task is persisted
+// under the requested ID.
+var ErrTaskNotFound = errors.New("task not found")
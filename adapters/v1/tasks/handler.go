@@ -0,0 +1,45 @@
+package tasks
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Handler serves GET /v1/tasks/{id}, returning a task's current state and
+// full transition history so callers don't need to know the integer step
+// indices the old SendStatus(ctx, step int) scheme required.
+type Handler struct {
+	manager *Manager
+}
+
+func NewHandler(manager *Manager) *Handler {
+	return &Handler{manager: manager}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	taskID := strings.TrimPrefix(r.URL.Path, "/v1/tasks/")
+	if taskID == "" || taskID == r.URL.Path {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	task, err := h.manager.Get(r.Context(), taskID)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(task)
+}
@@ -0,0 +1,56 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubescape/go-logger"
+	"github.com/kubescape/go-logger/helpers"
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/kubescape/kubevuln/core/ports"
+)
+
+// ResumableScan is the callback a Manager invokes for each task it finds
+// in-flight on startup; implementations re-enter the scan pipeline at
+// task.State rather than restarting from Queued.
+type ResumableScan func(ctx context.Context, task domain.ScanTask) error
+
+// Manager wraps a ports.TaskRepository with the ability to resume scans that
+// were interrupted mid-flight by a pod restart: on Resume, every task not in
+// a terminal state is handed to the ResumableScan passed to Resume.
+type Manager struct {
+	repository ports.TaskRepository
+}
+
+func NewManager(repository ports.TaskRepository) *Manager {
+	return &Manager{repository: repository}
+}
+
+// Resume looks up every in-flight task and re-enters it via resume. A
+// failure resuming one task is logged and does not stop the others from
+// being resumed.
+func (m *Manager) Resume(ctx context.Context, resume ResumableScan) error {
+	inFlight, err := m.repository.ListInFlight(ctx)
+	if err != nil {
+		return fmt.Errorf("listing in-flight tasks to resume: %w", err)
+	}
+
+	for _, task := range inFlight {
+		if err := resume(ctx, task); err != nil {
+			logger.L().Warning("failed to resume scan task",
+				helpers.String("taskID", task.ID), helpers.String("state", string(task.State)), helpers.Error(err))
+		}
+	}
+	return nil
+}
+
+// Transition persists a state change for taskID and returns the task's new
+// state.
+func (m *Manager) Transition(ctx context.Context, taskID string, toState domain.TaskState, detail string) (domain.ScanTask, error) {
+	return m.repository.TransitionTask(ctx, taskID, toState, detail)
+}
+
+// Get returns a task's current state and transition history.
+func (m *Manager) Get(ctx context.Context, taskID string) (domain.ScanTask, error) {
+	return m.repository.GetTask(ctx, taskID)
+}
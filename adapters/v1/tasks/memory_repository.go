@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/kubescape/kubevuln/core/ports"
+)
+
+// defaultTaskTTL bounds how long a terminal-state task (Done/Failed/
+// Canceled) is kept before MemoryRepository evicts it, so a long-running
+// process handling a continuous stream of scans doesn't grow the map
+// without bound.
+const defaultTaskTTL = time.Hour
+
+// MemoryRepository is an in-process ports.TaskRepository, used as the
+// default for single-replica deployments and in tests. It does not survive
+// a pod restart; use a SQLRepository for that. Terminal-state tasks are
+// evicted after ttl rather than kept for the lifetime of the process.
+type MemoryRepository struct {
+	mu         sync.RWMutex
+	tasks      map[string]domain.ScanTask
+	terminalAt map[string]time.Time
+	ttl        time.Duration
+}
+
+var _ ports.TaskRepository = (*MemoryRepository)(nil)
+
+// NewMemoryRepository builds a MemoryRepository that evicts terminal-state
+// tasks after defaultTaskTTL.
+func NewMemoryRepository() *MemoryRepository {
+	return NewMemoryRepositoryWithTTL(defaultTaskTTL)
+}
+
+// NewMemoryRepositoryWithTTL is like NewMemoryRepository but with an
+// explicit eviction window; ttl <= 0 disables eviction.
+func NewMemoryRepositoryWithTTL(ttl time.Duration) *MemoryRepository {
+	return &MemoryRepository{
+		tasks:      make(map[string]domain.ScanTask),
+		terminalAt: make(map[string]time.Time),
+		ttl:        ttl,
+	}
+}
+
+// evictExpiredLocked drops terminal-state tasks whose TTL has elapsed.
+// Callers must hold r.mu for writing.
+func (r *MemoryRepository) evictExpiredLocked() {
+	if r.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	for id, at := range r.terminalAt {
+		if now.Sub(at) >= r.ttl {
+			delete(r.tasks, id)
+			delete(r.terminalAt, id)
+		}
+	}
+}
+
+func (r *MemoryRepository) CreateTask(ctx context.Context, task domain.ScanTask) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictExpiredLocked()
+	if _, exists := r.tasks[task.ID]; exists {
+		return nil
+	}
+	if task.State == "" {
+		task.State = domain.TaskQueued
+	}
+	r.tasks[task.ID] = task
+	return nil
+}
+
+func (r *MemoryRepository) GetTask(ctx context.Context, taskID string) (domain.ScanTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	task, ok := r.tasks[taskID]
+	if !ok {
+		return domain.ScanTask{}, fmt.Errorf("task %s: %w", taskID, ErrTaskNotFound)
+	}
+	return task, nil
+}
+
+func (r *MemoryRepository) TransitionTask(ctx context.Context, taskID string, toState domain.TaskState, detail string) (domain.ScanTask, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.evictExpiredLocked()
+
+	task, ok := r.tasks[taskID]
+	if !ok {
+		task = domain.ScanTask{ID: taskID}
+	}
+	task.State = toState
+	task.Transitions = append(task.Transitions, domain.TaskTransition{
+		ActionID:  fmt.Sprintf("%d", len(task.Transitions)+1),
+		State:     toState,
+		Detail:    detail,
+		Timestamp: time.Now(),
+	})
+	r.tasks[taskID] = task
+
+	switch toState {
+	case domain.TaskDone, domain.TaskFailed, domain.TaskCanceled:
+		r.terminalAt[taskID] = time.Now()
+	default:
+		delete(r.terminalAt, taskID)
+	}
+	return task, nil
+}
+
+func (r *MemoryRepository) ListInFlight(ctx context.Context) ([]domain.ScanTask, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var inFlight []domain.ScanTask
+	for _, task := range r.tasks {
+		switch task.State {
+		case domain.TaskDone, domain.TaskFailed, domain.TaskCanceled:
+			continue
+		default:
+			inFlight = append(inFlight, task)
+		}
+	}
+	return inFlight, nil
+}
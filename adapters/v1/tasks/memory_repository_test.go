@@ -0,0 +1,50 @@
+package tasks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryRepository_CreateTask_Idempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepository()
+
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-1", JobID: "job-1", ParentJobID: "parent-1"}))
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-1", JobID: "job-2", ParentJobID: "parent-2"}))
+
+	task, err := repo.GetTask(ctx, "task-1")
+	require.NoError(t, err)
+	require.Equal(t, "job-1", task.JobID)
+	require.Equal(t, "parent-1", task.ParentJobID)
+}
+
+// TestMemoryRepository_EvictsTerminalTaskAfterTTL proves evictExpiredLocked
+// actually drops a terminal-state task once its TTL has elapsed, rather than
+// keeping it (and every other terminal task) for the life of the process.
+func TestMemoryRepository_EvictsTerminalTaskAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	repo := NewMemoryRepositoryWithTTL(10 * time.Millisecond)
+
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-1", JobID: "job-1"}))
+	_, err := repo.TransitionTask(ctx, "task-1", domain.TaskDone, "done")
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	// eviction is lazy, only run by CreateTask/TransitionTask - a second,
+	// unrelated task drives it without relying on task-1's own bookkeeping.
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-2", JobID: "job-2"}))
+
+	_, err = repo.GetTask(ctx, "task-1")
+	require.ErrorIs(t, err, ErrTaskNotFound)
+
+	inFlight, err := repo.ListInFlight(ctx)
+	require.NoError(t, err)
+	for _, task := range inFlight {
+		require.NotEqual(t, "task-1", task.ID)
+	}
+}
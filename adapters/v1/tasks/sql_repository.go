@@ -0,0 +1,149 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	// database/sql drivers for the "pgx"/"sqlite" DSNs NewSQLRepository's
+	// callers open, matching adapters/v1/postprocessors.NewPostgresStore/
+	// NewSQLiteStore.
+	_ "github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
+
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/kubescape/kubevuln/core/ports"
+)
+
+// SQLRepository is a database/sql-backed ports.TaskRepository. Unlike
+// MemoryRepository it survives a pod restart, which is what lets a task
+// manager resume interrupted scans: callers are expected to have applied
+// migrations/0001_scan_task_schema.sql.
+type SQLRepository struct {
+	db *sql.DB
+}
+
+var _ ports.TaskRepository = (*SQLRepository)(nil)
+
+// NewSQLRepository wraps an already-open *sql.DB (Postgres via "pgx" or
+// SQLite via "sqlite", matching the drivers used by
+// adapters/v1/postprocessors.NewPostgresStore/NewSQLiteStore) as a
+// ports.TaskRepository.
+func NewSQLRepository(db *sql.DB) *SQLRepository {
+	return &SQLRepository{db: db}
+}
+
+func (r *SQLRepository) CreateTask(ctx context.Context, task domain.ScanTask) error {
+	if task.State == "" {
+		task.State = domain.TaskQueued
+	}
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO scan_task (id, job_id, parent_job_id, state)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO NOTHING
+	`, task.ID, task.JobID, task.ParentJobID, string(task.State))
+	return err
+}
+
+func (r *SQLRepository) GetTask(ctx context.Context, taskID string) (domain.ScanTask, error) {
+	var task domain.ScanTask
+	var state string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, job_id, parent_job_id, state FROM scan_task WHERE id = $1
+	`, taskID).Scan(&task.ID, &task.JobID, &task.ParentJobID, &state)
+	if err == sql.ErrNoRows {
+		return domain.ScanTask{}, fmt.Errorf("task %s: %w", taskID, ErrTaskNotFound)
+	}
+	if err != nil {
+		return domain.ScanTask{}, fmt.Errorf("getting task %s: %w", taskID, err)
+	}
+	task.State = domain.TaskState(state)
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT action_id, state, detail, timestamp FROM scan_task_transition
+		WHERE task_id = $1 ORDER BY timestamp ASC
+	`, taskID)
+	if err != nil {
+		return domain.ScanTask{}, fmt.Errorf("getting transitions for task %s: %w", taskID, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.TaskTransition
+		var state string
+		if err := rows.Scan(&t.ActionID, &state, &t.Detail, &t.Timestamp); err != nil {
+			return domain.ScanTask{}, fmt.Errorf("scanning transition for task %s: %w", taskID, err)
+		}
+		t.State = domain.TaskState(state)
+		task.Transitions = append(task.Transitions, t)
+	}
+	return task, rows.Err()
+}
+
+func (r *SQLRepository) TransitionTask(ctx context.Context, taskID string, toState domain.TaskState, detail string) (domain.ScanTask, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domain.ScanTask{}, fmt.Errorf("beginning transition transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO scan_task (id, job_id, parent_job_id, state)
+		VALUES ($1, '', '', $2)
+		ON CONFLICT (id) DO UPDATE SET state = excluded.state
+	`, taskID, string(toState)); err != nil {
+		return domain.ScanTask{}, fmt.Errorf("upserting task %s: %w", taskID, err)
+	}
+
+	var actionCount int
+	if err := tx.QueryRowContext(ctx, `SELECT count(*) FROM scan_task_transition WHERE task_id = $1`, taskID).Scan(&actionCount); err != nil {
+		return domain.ScanTask{}, fmt.Errorf("counting transitions for task %s: %w", taskID, err)
+	}
+
+	actionID := fmt.Sprintf("%d", actionCount+1)
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO scan_task_transition (task_id, action_id, state, detail, timestamp)
+		VALUES ($1, $2, $3, $4, $5)
+	`, taskID, actionID, string(toState), detail, time.Now()); err != nil {
+		return domain.ScanTask{}, fmt.Errorf("recording transition for task %s: %w", taskID, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domain.ScanTask{}, fmt.Errorf("committing transition for task %s: %w", taskID, err)
+	}
+
+	return r.GetTask(ctx, taskID)
+}
+
+func (r *SQLRepository) ListInFlight(ctx context.Context) ([]domain.ScanTask, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id FROM scan_task WHERE state NOT IN ($1, $2, $3)
+	`, string(domain.TaskDone), string(domain.TaskFailed), string(domain.TaskCanceled))
+	if err != nil {
+		return nil, fmt.Errorf("listing in-flight tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning in-flight task id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]domain.ScanTask, 0, len(ids))
+	for _, id := range ids {
+		task, err := r.GetTask(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,72 @@
+package tasks
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/kubescape/kubevuln/core/domain"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSQLRepository(t *testing.T) *SQLRepository {
+	t.Helper()
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	// :memory: sqlite databases are per-connection; pin the pool to one
+	// connection so the schema applied below is visible to every query.
+	db.SetMaxOpenConns(1)
+
+	schema, err := os.ReadFile("migrations/0001_scan_task_schema.sql")
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, string(schema))
+	require.NoError(t, err)
+
+	return NewSQLRepository(db)
+}
+
+func TestSQLRepository_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestSQLRepository(t)
+
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-1", JobID: "job-1", ParentJobID: "parent-1"}))
+	// creating the same task again must be a no-op, not overwrite job_id/parent_job_id
+	require.NoError(t, repo.CreateTask(ctx, domain.ScanTask{ID: "task-1", JobID: "job-2", ParentJobID: "parent-2"}))
+
+	task, err := repo.GetTask(ctx, "task-1")
+	require.NoError(t, err)
+	require.Equal(t, "job-1", task.JobID)
+	require.Equal(t, "parent-1", task.ParentJobID)
+	require.Equal(t, domain.TaskQueued, task.State)
+
+	updated, err := repo.TransitionTask(ctx, "task-1", domain.TaskScanning, "scanning image")
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskScanning, updated.State)
+	require.Equal(t, "job-1", updated.JobID)
+	require.Len(t, updated.Transitions, 1)
+	require.Equal(t, "1", updated.Transitions[0].ActionID)
+
+	_, err = repo.TransitionTask(ctx, "task-1", domain.TaskDone, "done")
+	require.NoError(t, err)
+
+	final, err := repo.GetTask(ctx, "task-1")
+	require.NoError(t, err)
+	require.Equal(t, domain.TaskDone, final.State)
+	require.Len(t, final.Transitions, 2)
+	require.Equal(t, "2", final.Transitions[1].ActionID)
+
+	inFlight, err := repo.ListInFlight(ctx)
+	require.NoError(t, err)
+	require.Empty(t, inFlight)
+}
+
+func TestSQLRepository_GetTask_NotFound(t *testing.T) {
+	repo := newTestSQLRepository(t)
+	_, err := repo.GetTask(context.Background(), "missing")
+	require.ErrorIs(t, err, ErrTaskNotFound)
+}
@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// LogFormat selects the output encoding used by loggers returned from
+// LoggerFromContext.
+type LogFormat int
+
+const (
+	LogFormatJSON LogFormat = iota
+	LogFormatLogfmt
+)
+
+var currentLevel int32 = int32(hclog.Info)
+
+// SetLogLevel changes the level every future LoggerFromContext call returns,
+// backing the `POST /debug/log-level` endpoint: large scan bodies can be
+// toggled to Trace and back without recompiling or restarting.
+func SetLogLevel(level hclog.Level) {
+	atomic.StoreInt32(&currentLevel, int32(level))
+}
+
+// CurrentLogLevel returns the level last set via SetLogLevel.
+func CurrentLogLevel() hclog.Level {
+	return hclog.Level(atomic.LoadInt32(&currentLevel))
+}
+
+var base atomic.Pointer[hclog.Logger]
+
+func init() {
+	l := hclog.New(&hclog.LoggerOptions{
+		Name:       "kubevuln",
+		Level:      hclog.Info,
+		JSONFormat: true,
+		Output:     os.Stderr,
+	})
+	base.Store(&l)
+}
+
+// SetLogFormat switches every future LoggerFromContext call between JSON and
+// logfmt output. Safe to call concurrently with LoggerFromContext.
+func SetLogFormat(format LogFormat) {
+	l := hclog.New(&hclog.LoggerOptions{
+		Name:       "kubevuln",
+		Level:      hclog.Info,
+		JSONFormat: format == LogFormatJSON,
+		Output:     os.Stderr,
+	})
+	base.Store(&l)
+}
+
+// LoggerFromContext returns an hclog.Logger pre-bound with the scan
+// correlation fields - scanID, wlid, containerName, imageHash, jobID and
+// parentJobID - already threaded through ctx by SubmitCVE/SendStatus. Every
+// log line emitted from it can then be grepped by a single scanID across
+// BackendAdapter, the SBOM creator and the syft/grype adapters.
+func LoggerFromContext(ctx context.Context) hclog.Logger {
+	args := make([]interface{}, 0, 12)
+
+	if scanID, ok := ctx.Value(ScanIDKey{}).(string); ok {
+		args = append(args, "scanID", scanID)
+	}
+	if workload, ok := ctx.Value(WorkloadKey{}).(ScanCommand); ok {
+		args = append(args,
+			"wlid", workload.Wlid,
+			"containerName", workload.ContainerName,
+			"imageHash", workload.ImageHash,
+			"jobID", workload.JobID,
+			"parentJobID", workload.ParentJobID,
+		)
+	}
+
+	l := (*base.Load()).With(args...)
+	l.SetLevel(CurrentLogLevel())
+	return l
+}
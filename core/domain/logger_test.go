@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerFromContext_BindsCorrelationFields(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ScanIDKey{}, "scan-1")
+	ctx = context.WithValue(ctx, WorkloadKey{}, ScanCommand{
+		Wlid:          "wlid://cluster-1/namespace-1/deployment-1",
+		ContainerName: "container-1",
+		ImageHash:     "sha256:abc",
+		JobID:         "job-1",
+		ParentJobID:   "parent-1",
+	})
+
+	log := LoggerFromContext(ctx)
+
+	require.True(t, log.IsDebug() || log.IsInfo() || log.IsWarn() || log.IsError(),
+		"logger should have some level set")
+	require.True(t, log.ImpliedArgs() != nil)
+
+	args := log.ImpliedArgs()
+	asMap := map[string]interface{}{}
+	for i := 0; i+1 < len(args); i += 2 {
+		key, ok := args[i].(string)
+		require.True(t, ok)
+		asMap[key] = args[i+1]
+	}
+
+	require.Equal(t, "scan-1", asMap["scanID"])
+	require.Equal(t, "wlid://cluster-1/namespace-1/deployment-1", asMap["wlid"])
+	require.Equal(t, "container-1", asMap["containerName"])
+	require.Equal(t, "sha256:abc", asMap["imageHash"])
+	require.Equal(t, "job-1", asMap["jobID"])
+	require.Equal(t, "parent-1", asMap["parentJobID"])
+}
+
+func TestLoggerFromContext_NoWorkload_OmitsWorkloadFields(t *testing.T) {
+	log := LoggerFromContext(context.Background())
+	require.Empty(t, log.ImpliedArgs())
+}
+
+func TestSetLogLevel_ChangesCurrentLogLevel(t *testing.T) {
+	original := CurrentLogLevel()
+	t.Cleanup(func() { SetLogLevel(original) })
+
+	SetLogLevel(hclog.Trace)
+	require.Equal(t, hclog.Trace, CurrentLogLevel())
+
+	log := LoggerFromContext(context.Background())
+	require.Equal(t, hclog.Trace, log.GetLevel())
+}
+
+// TestSetLogFormat_ConcurrentWithLoggerFromContext exercises SetLogFormat
+// and LoggerFromContext from many goroutines at once - run with -race, it
+// catches the base logger being swapped without synchronization.
+func TestSetLogFormat_ConcurrentWithLoggerFromContext(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			format := LogFormatJSON
+			if i%2 == 0 {
+				format = LogFormatLogfmt
+			}
+			SetLogFormat(format)
+		}(i)
+		go func() {
+			defer wg.Done()
+			LoggerFromContext(context.Background())
+		}()
+	}
+	wg.Wait()
+}
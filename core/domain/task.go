@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+// TaskState is one step in a ScanTask's lifecycle.
+type TaskState string
+
+const (
+	TaskQueued         TaskState = "Queued"
+	TaskSBOMGenerating TaskState = "SBOMGenerating"
+	TaskSBOMReady      TaskState = "SBOMReady"
+	TaskScanning       TaskState = "Scanning"
+	TaskEnriching      TaskState = "Enriching"
+	TaskSubmitting     TaskState = "Submitting"
+	TaskDone           TaskState = "Done"
+	TaskFailed         TaskState = "Failed"
+	TaskCanceled       TaskState = "Canceled"
+)
+
+// ScanTask is the persisted state of a single scan. It replaces the
+// previous SendStatus(ctx, step int) scheme of two parallel details/statuses
+// arrays and a LastAction+1 counter with an explicit, queryable state plus a
+// full transition history, so a new lifecycle stage only needs a new
+// TaskState rather than edits to two arrays in lock-step.
+type ScanTask struct {
+	ID          string
+	JobID       string
+	ParentJobID string
+	State       TaskState
+	Transitions []TaskTransition
+}
+
+// TaskTransition records a single state change, so GET /v1/tasks/{id} can
+// return the full history rather than just the current state.
+type TaskTransition struct {
+	ActionID  string
+	State     TaskState
+	Detail    string
+	Timestamp time.Time
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// EventSink delivers a single CloudEvent produced during a scan - a status
+// transition or a chunk of vulnerability results - to wherever it needs to
+// go next: an HTTP event receiver, a message broker, or anything else that
+// can accept a CloudEvent. BackendAdapter depends on this interface rather
+// than on httputils.HttpPost directly, so the transport can be swapped
+// without touching scan logic.
+type EventSink interface {
+	Send(ctx context.Context, event cloudevents.Event) error
+}
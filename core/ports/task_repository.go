@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/kubescape/kubevuln/core/domain"
+)
+
+// TaskRepository persists ScanTask state so an interrupted scan (e.g. a pod
+// restart) can be resumed from its last persisted state instead of starting
+// over, or silently losing track of where a scan was.
+type TaskRepository interface {
+	// CreateTask persists a brand new task in the domain.TaskQueued state.
+	CreateTask(ctx context.Context, task domain.ScanTask) error
+	// GetTask returns the task's current state and full transition history.
+	GetTask(ctx context.Context, taskID string) (domain.ScanTask, error)
+	// TransitionTask moves taskID to toState, appending a TaskTransition to
+	// its history, and returns the task's new state.
+	TransitionTask(ctx context.Context, taskID string, toState domain.TaskState, detail string) (domain.ScanTask, error)
+	// ListInFlight returns every task that isn't in a terminal state
+	// (Done, Failed, Canceled), so a task manager can resume them after a
+	// restart.
+	ListInFlight(ctx context.Context) ([]domain.ScanTask, error)
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+)
+
+// relevancyIndexKey is the context key RelevancyPostprocessor reads the set
+// of relevant CVE names from. It is kept request-scoped (on ctx) rather than
+// a mutable field so concurrent scans never step on each other's relevancy
+// data.
+type relevancyIndexKey struct{}
+
+// ContextWithRelevancyIndex stashes the set of CVE names considered relevant
+// for the current scan (derived from a relevancy-filtered manifest) on ctx,
+// for RelevancyPostprocessor to pick up.
+func ContextWithRelevancyIndex(ctx context.Context, index map[string]struct{}) context.Context {
+	return context.WithValue(ctx, relevancyIndexKey{}, index)
+}
+
+// RelevancyIndexFromContext returns the relevancy index stashed by
+// ContextWithRelevancyIndex, and whether one was present.
+func RelevancyIndexFromContext(ctx context.Context) (map[string]struct{}, bool) {
+	index, ok := ctx.Value(relevancyIndexKey{}).(map[string]struct{})
+	return index, ok
+}
+
+// ExceptionPostprocessor re-states exception application as an explicit,
+// swappable chain stage. It is a no-op today because exceptions are already
+// applied inline when the scanner output is converted into vulnerabilities;
+// it exists so that step is visible in the pipeline and can be swapped
+// independently (e.g. different expiry semantics) without touching the
+// conversion code.
+type ExceptionPostprocessor struct{}
+
+func NewExceptionPostprocessor() *ExceptionPostprocessor {
+	return &ExceptionPostprocessor{}
+}
+
+func (p *ExceptionPostprocessor) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	return report, vulnerabilities, nil
+}
+
+// RelevancyPostprocessor marks each vulnerability relevant when its CVE ID
+// appears in the index stashed on ctx by ContextWithRelevancyIndex. When no
+// index is present (no relevancy data for this scan) it leaves
+// vulnerabilities untouched.
+type RelevancyPostprocessor struct{}
+
+func NewRelevancyPostprocessor() *RelevancyPostprocessor {
+	return &RelevancyPostprocessor{}
+}
+
+func (p *RelevancyPostprocessor) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	index, ok := RelevancyIndexFromContext(ctx)
+	if !ok {
+		return report, vulnerabilities, nil
+	}
+	for i, v := range vulnerabilities {
+		_, isRelevant := index[v.Name]
+		vulnerabilities[i].IsRelevant = &isRelevant
+	}
+	return report, vulnerabilities, nil
+}
+
+// SBOMPackageInfo is the subset of SBOM package metadata
+// SBOMCrossReferencePostprocessor attaches to matching vulnerabilities.
+type SBOMPackageInfo struct {
+	Purl     string
+	Licenses []string
+}
+
+// SBOMLookup resolves a package name+version to the SBOM metadata recorded
+// for the current scan, e.g. backed by the syft SBOM kubevuln already
+// generates for the image.
+type SBOMLookup func(ctx context.Context, packageName, packageVersion string) (SBOMPackageInfo, bool)
+
+// SBOMCrossReferencePostprocessor attaches package purl and license
+// metadata from the scan's SBOM to each vulnerability, so consumers don't
+// have to re-parse the SBOM to answer "what license is this affected
+// package under".
+type SBOMCrossReferencePostprocessor struct {
+	Lookup SBOMLookup
+}
+
+func NewSBOMCrossReferencePostprocessor(lookup SBOMLookup) *SBOMCrossReferencePostprocessor {
+	return &SBOMCrossReferencePostprocessor{Lookup: lookup}
+}
+
+func (p *SBOMCrossReferencePostprocessor) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	if p.Lookup == nil {
+		return report, vulnerabilities, nil
+	}
+	for i, v := range vulnerabilities {
+		info, ok := p.Lookup(ctx, v.PackageName, v.PackageVersion)
+		if !ok {
+			continue
+		}
+		vulnerabilities[i].PackagePURL = info.Purl
+		vulnerabilities[i].Licenses = info.Licenses
+	}
+	return report, vulnerabilities, nil
+}
+
+// SinkMode selects where SinkSelectorPostprocessor routes a processed report.
+type SinkMode int
+
+const (
+	SinkModeEventReceiver SinkMode = iota
+	SinkModeRelationalStore
+	SinkModeBroker
+)
+
+// ReportSender delivers a fully postprocessed report/vulnerability list to a
+// destination; BackendAdapter's event-receiver send, RelationalAdapter's
+// store writes, and a CloudEvents broker publish all fit this shape.
+type ReportSender func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) error
+
+// SinkSelectorPostprocessor is a terminal chain stage: based on the
+// configured SinkMode it forwards the now fully-postprocessed report to the
+// HTTP event receiver, the relational store, or a CloudEvents broker,
+// without mutating the report itself. It lets an orchestrator fan one
+// pipeline out to whichever destination a tenant is configured for instead
+// of wiring a separate chain per destination.
+type SinkSelectorPostprocessor struct {
+	Mode           SinkMode
+	SendToReceiver ReportSender
+	SendToStore    ReportSender
+	SendToBroker   ReportSender
+}
+
+func NewSinkSelectorPostprocessor(mode SinkMode, toReceiver, toStore, toBroker ReportSender) *SinkSelectorPostprocessor {
+	return &SinkSelectorPostprocessor{Mode: mode, SendToReceiver: toReceiver, SendToStore: toStore, SendToBroker: toBroker}
+}
+
+func (p *SinkSelectorPostprocessor) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	send := p.SendToReceiver
+	switch p.Mode {
+	case SinkModeRelationalStore:
+		send = p.SendToStore
+	case SinkModeBroker:
+		send = p.SendToBroker
+	}
+	if send == nil {
+		return report, vulnerabilities, nil
+	}
+	if err := send(ctx, report, vulnerabilities); err != nil {
+		return nil, nil, err
+	}
+	return report, vulnerabilities, nil
+}
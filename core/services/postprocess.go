@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+)
+
+// ReportPostprocessor transforms a scan's report and vulnerability list
+// before it is handed off to a sink. Built-in stages cover exception
+// application and relevancy marking (see NewExceptionPostprocessor and
+// NewRelevancyPostprocessor), summarization (registered by BackendAdapter
+// as its "summarize" stage), plus an SBOM cross-reference stage and a sink
+// selector; callers can register their own (e.g. a CISA KEV enricher or an
+// EPSS score attacher) via RegisterPostprocessor.
+type ReportPostprocessor interface {
+	Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error)
+}
+
+// ReportPostprocessorFunc adapts a plain function to ReportPostprocessor,
+// mirroring http.HandlerFunc, so simple stages don't need a named type.
+type ReportPostprocessorFunc func(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error)
+
+func (f ReportPostprocessorFunc) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	return f(ctx, report, vulnerabilities)
+}
+
+type stage struct {
+	name    string
+	stage   ReportPostprocessor
+	enabled bool
+}
+
+// PostprocessorChain runs an ordered, per-customer configurable sequence of
+// ReportPostprocessor stages over a scan's report, replacing what used to be
+// a single hardcoded pipeline (domainToArmo -> relevancy merge -> summarize).
+type PostprocessorChain struct {
+	mu     sync.RWMutex
+	stages []stage
+}
+
+func NewPostprocessorChain() *PostprocessorChain {
+	return &PostprocessorChain{}
+}
+
+// Register appends a named stage to the chain. Registering the same name
+// twice replaces the earlier stage in place, so callers can override a
+// built-in (e.g. swap the default summarizer) without having to reorder the
+// chain afterwards.
+func (c *PostprocessorChain) Register(name string, p ReportPostprocessor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.stages {
+		if s.name == name {
+			c.stages[i].stage = p
+			return
+		}
+	}
+	c.stages = append(c.stages, stage{name: name, stage: p, enabled: true})
+}
+
+// SetEnabled toggles a stage on or off without removing it from the chain,
+// so tenants can opt into heavier enrichment via clusterConfig.
+func (c *PostprocessorChain) SetEnabled(name string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, s := range c.stages {
+		if s.name == name {
+			c.stages[i].enabled = enabled
+			return
+		}
+	}
+}
+
+// Reorder moves the named stages to the front, in the given order; any
+// registered stage not listed keeps its relative position at the end.
+func (c *PostprocessorChain) Reorder(names ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := make(map[string]int, len(names))
+	for i, n := range names {
+		index[n] = i
+	}
+	sort.SliceStable(c.stages, func(i, j int) bool {
+		pi, oki := index[c.stages[i].name]
+		pj, okj := index[c.stages[j].name]
+		if oki && okj {
+			return pi < pj
+		}
+		return oki && !okj
+	})
+}
+
+// Clone returns an independent copy of the chain's current stages, so a
+// caller can seed its own chain from a shared default without the two
+// chains stepping on each other afterwards.
+func (c *PostprocessorChain) Clone() *PostprocessorChain {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	clone := NewPostprocessorChain()
+	clone.stages = append([]stage(nil), c.stages...)
+	return clone
+}
+
+// Process runs every enabled stage over report/vulnerabilities in order,
+// threading the (possibly transformed) result from one stage into the next.
+func (c *PostprocessorChain) Process(ctx context.Context, report *v1.ScanResultReport, vulnerabilities []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+	c.mu.RLock()
+	stages := make([]stage, len(c.stages))
+	copy(stages, c.stages)
+	c.mu.RUnlock()
+
+	var err error
+	for _, s := range stages {
+		if !s.enabled {
+			continue
+		}
+		report, vulnerabilities, err = s.stage.Process(ctx, report, vulnerabilities)
+		if err != nil {
+			return nil, nil, fmt.Errorf("postprocessor %q: %w", s.name, err)
+		}
+	}
+	return report, vulnerabilities, nil
+}
+
+var defaultChain = NewPostprocessorChain()
+
+// RegisterPostprocessor adds p to the package-level default chain, so users
+// can register their own stages (e.g. a CISA KEV enricher or an EPSS score
+// attacher) at startup without wiring a custom chain into every adapter.
+// Adapters seed their own chain from DefaultChain via Clone, so stages
+// registered here before the adapter is constructed are picked up
+// automatically.
+func RegisterPostprocessor(name string, p ReportPostprocessor) {
+	defaultChain.Register(name, p)
+}
+
+// DefaultChain returns the package-level chain new adapters seed their own
+// chain from.
+func DefaultChain() *PostprocessorChain {
+	return defaultChain
+}
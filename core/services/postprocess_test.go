@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	cs "github.com/armosec/armoapi-go/containerscan"
+	v1 "github.com/armosec/armoapi-go/containerscan/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func markerStage(name string, vulnerabilities *[]string) ReportPostprocessorFunc {
+	return func(ctx context.Context, report *v1.ScanResultReport, vulns []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+		*vulnerabilities = append(*vulnerabilities, name)
+		return report, vulns, nil
+	}
+}
+
+func TestPostprocessorChain_OrderAndDisable(t *testing.T) {
+	var order []string
+	chain := NewPostprocessorChain()
+	chain.Register("a", markerStage("a", &order))
+	chain.Register("b", markerStage("b", &order))
+	chain.Register("c", markerStage("c", &order))
+
+	chain.Reorder("c", "a")
+	_, _, err := chain.Process(context.Background(), &v1.ScanResultReport{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "a", "b"}, order)
+
+	order = nil
+	chain.SetEnabled("a", false)
+	_, _, err = chain.Process(context.Background(), &v1.ScanResultReport{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, order)
+}
+
+func TestPostprocessorChain_SummarizeDisabled_LeavesSummaryNil(t *testing.T) {
+	// Mirrors BackendAdapter.ConfigurePostprocessors(nil, []string{"summarize"}):
+	// a disabled "summarize" stage must be skipped entirely, leaving the
+	// report's Summary at its original nil value rather than panicking
+	// whatever dereferences it downstream.
+	chain := NewPostprocessorChain()
+	var summarizeCalled bool
+	chain.Register("summarize", ReportPostprocessorFunc(func(ctx context.Context, report *v1.ScanResultReport, vulns []cs.CommonContainerVulnerabilityResult) (*v1.ScanResultReport, []cs.CommonContainerVulnerabilityResult, error) {
+		summarizeCalled = true
+		return report, vulns, nil
+	}))
+	chain.SetEnabled("summarize", false)
+
+	report, _, err := chain.Process(context.Background(), &v1.ScanResultReport{}, nil)
+	require.NoError(t, err)
+	assert.False(t, summarizeCalled)
+	assert.Nil(t, report.Summary)
+}
+
+func TestPostprocessorChain_Clone_IsIndependent(t *testing.T) {
+	base := NewPostprocessorChain()
+	base.Register("a", markerStage("a", &[]string{}))
+
+	clone := base.Clone()
+	clone.SetEnabled("a", false)
+
+	var order []string
+	base.Register("a", markerStage("a", &order))
+	_, _, err := base.Process(context.Background(), &v1.ScanResultReport{}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, order)
+}